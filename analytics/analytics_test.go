@@ -0,0 +1,154 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flaviostutz/sort"
+)
+
+//box returns a 10x20 box whose bottom center is x,y
+func box(id int64, x, y float64) sort.Track {
+	return sort.Track{ID: id, BBox: []float64{x - 5, y - 20, x + 5, y}, Score: 1, Class: "person"}
+}
+
+func TestLineCounter(t *testing.T) {
+	//horizontal line. Moving down the image crosses from left to right looking from A to B
+	c, err := NewLineCounter(AnchorBottomCenter, Line{Name: "door", A: Point{0, 100}, B: Point{200, 100}, Hysteresis: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//track 1 walks down through the line jittering around it. track 2 walks up outside the segment
+	ys := []float64{80, 90, 98, 103, 97, 104, 110, 120}
+	events := make([]Crossing, 0)
+	for i, y := range ys {
+		events = append(events, c.Update(i+1, []sort.Track{box(1, 50, y), box(2, 300, 200-y)})...)
+	}
+	if len(events) != 1 {
+		t.Fatalf("jittering track should be counted once. events=%v", events)
+	}
+	e := events[0]
+	if e.Line != "door" || e.TrackID != 1 || e.Direction != In || e.Frame != 7 || e.Class != "person" {
+		t.Errorf("unexpected crossing %+v", e)
+	}
+
+	//track 1 walks back up
+	events = c.Update(9, []sort.Track{box(1, 50, 90)})
+	if len(events) != 1 || events[0].Direction != Out {
+		t.Errorf("expected crossing out. events=%v", events)
+	}
+	if c.Totals()["door"] != (Totals{In: 1, Out: 1}) {
+		t.Errorf("unexpected totals %v", c.Totals())
+	}
+
+	_, err = NewLineCounter(AnchorCentroid, Line{Name: "a", A: Point{0, 0}, B: Point{0, 0}})
+	if err == nil {
+		t.Errorf("lines without length should be rejected")
+	}
+	_, err = NewLineCounter("top", Line{Name: "a", A: Point{0, 0}, B: Point{1, 0}})
+	if err == nil {
+		t.Errorf("unknown anchors should be rejected")
+	}
+}
+
+func TestOtherBoxes(t *testing.T) {
+	c, err := NewLineCounter(AnchorBottomCenter, Line{Name: "door", A: Point{0, 100}, B: Point{200, 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewZoneMonitor(AnchorBottomCenter, Zone{Name: "queue", Polygon: []Point{{0, 110}, {200, 110}, {200, 200}, {0, 200}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossings := make([]Crossing, 0)
+	zone := make([]ZoneEvent, 0)
+	for f, y := range []float64{80, 90, 100, 110} {
+		tracks := []sort.Track{
+			//point [x,y,r], anchored at the bottom of its circle
+			{ID: 1, BBox: []float64{50, y, 5}},
+			//oriented box [cx,cy,w,h,angle] turned by 90 degrees, so its enclosing box is 20 high
+			{ID: 2, BBox: []float64{100, y, 20, 10, math.Pi / 2}},
+			//3D boxes are not on the image
+			{ID: 3, BBox: []float64{150, y, 0, 4, 2, 2, 0}},
+		}
+		crossings = append(crossings, c.Update(f+1, tracks)...)
+		zone = append(zone, m.Update(f+1, tracks)...)
+	}
+	//anchored on their centers, both tracks would only cross the line in frame 4
+	if len(crossings) != 2 || crossings[0].TrackID != 1 || crossings[0].Frame != 3 || crossings[1].TrackID != 2 || crossings[1].Frame != 3 {
+		t.Errorf("points and oriented boxes should cross by their enclosing box. crossings=%+v", crossings)
+	}
+	if len(zone) != 2 || zone[0].TrackID != 2 || zone[0].Frame != 3 || zone[1].TrackID != 1 || zone[1].Frame != 4 {
+		t.Errorf("points and oriented boxes should enter zones by their enclosing box. events=%+v", zone)
+	}
+}
+
+func TestZoneMonitor(t *testing.T) {
+	square := []Point{{0, 0}, {100, 0}, {100, 100}, {0, 100}}
+	m, err := NewZoneMonitor(AnchorBottomCenter, Zone{Name: "queue", Polygon: square, LoiterAfter: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//track 1 walks in and stays. track 2 passes through. track 3 is never inside
+	events := make([]ZoneEvent, 0)
+	xs2 := []float64{-20, 50, 50, 150, 150, 150}
+	for f := 1; f <= 6; f++ {
+		x1 := 50.0
+		if f == 1 {
+			x1 = -50
+		}
+		events = append(events, m.Update(f, []sort.Track{box(1, x1, 50), box(2, xs2[f-1], 50), box(3, 500, 500)})...)
+	}
+	expected := []ZoneEvent{
+		{Zone: "queue", TrackID: 1, Type: Enter, Frame: 2, Class: "person", Dwell: 1},
+		{Zone: "queue", TrackID: 2, Type: Enter, Frame: 2, Class: "person", Dwell: 1},
+		{Zone: "queue", TrackID: 2, Type: Exit, Frame: 4, Class: "person", Dwell: 2},
+		{Zone: "queue", TrackID: 1, Type: Loitering, Frame: 5, Class: "person", Dwell: 4},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("event %d: expected %+v, got %+v", i, expected[i], events[i])
+		}
+	}
+	if m.Occupancy()["queue"] != 1 {
+		t.Errorf("unexpected occupancy %v", m.Occupancy())
+	}
+	if d, ok := m.Dwell("queue", 1); !ok || d != 5 {
+		t.Errorf("unexpected dwell %d", d)
+	}
+
+	//track 1 disappears
+	m.ForgetAfter = 2
+	events = make([]ZoneEvent, 0)
+	for f := 7; f <= 10; f++ {
+		events = append(events, m.Update(f, []sort.Track{})...)
+	}
+	if len(events) != 1 || events[0].Type != Exit || events[0].Frame != 9 || m.Occupancy()["queue"] != 0 {
+		t.Errorf("missing track should exit. events=%+v", events)
+	}
+
+	_, err = NewZoneMonitor(AnchorCentroid, Zone{Name: "a", Polygon: square[:2]})
+	if err == nil {
+		t.Errorf("zones with less than 3 points should be rejected")
+	}
+}
+
+func TestSpeedMonitor(t *testing.T) {
+	m, err := NewSpeedMonitor(10, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	speeds := []float64{5, 12, 13, 14, 8, 12, 12}
+	events := make([]SpeedEvent, 0)
+	for i, v := range speeds {
+		events = append(events, m.Update(i+1, []sort.Track{{ID: 1, BBox: []float64{0, 0, 1, 1}, WorldSpeed: v, Speed: 100}})...)
+	}
+	if len(events) != 2 || events[0].Frame != 3 || events[0].Speed != 13 || events[1].Frame != 7 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
@@ -0,0 +1,99 @@
+//Package analytics turns the tracks reported by SORT into events such as line crossings and zone presence
+package analytics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/flaviostutz/sort"
+)
+
+//Point is a position in image coordinates (y grows downwards)
+type Point struct {
+	X float64 `json:"x" yaml:"x"`
+	Y float64 `json:"y" yaml:"y"`
+}
+
+//Anchor is the point of a box used to locate an object
+type Anchor string
+
+const (
+	//AnchorBottomCenter is the middle of the bottom edge, where people and vehicles touch the ground
+	AnchorBottomCenter Anchor = "bottom-center"
+	//AnchorCentroid is the center of the box
+	AnchorCentroid Anchor = "centroid"
+)
+
+//Validate checks that the anchor is known
+func (a Anchor) Validate() error {
+	if a != AnchorBottomCenter && a != AnchorCentroid {
+		return fmt.Errorf("unknown anchor %q", a)
+	}
+	return nil
+}
+
+//Point returns the anchor point of a box in the form [x1,y1,x2,y2]. Points [x,y,r] and oriented boxes [cx,cy,w,h,angle]
+//are anchored on their enclosing box. ok is false for boxes that are not on the image, as 3D boxes
+func (a Anchor) Point(bbox []float64) (p Point, ok bool) {
+	b := imageBox(bbox)
+	if b == nil {
+		return Point{}, false
+	}
+	x := (b[0] + b[2]) / 2
+	if a == AnchorCentroid {
+		return Point{X: x, Y: (b[1] + b[3]) / 2}, true
+	}
+	return Point{X: x, Y: b[3]}, true
+}
+
+//imageBox returns the [x1,y1,x2,y2] box enclosing a box tracked by one of the sort motion models, which are told
+//apart by their number of values, or nil for boxes that are not on the image
+func imageBox(bbox []float64) []float64 {
+	switch len(bbox) {
+	case 3:
+		r := bbox[2]
+		return []float64{bbox[0] - r, bbox[1] - r, bbox[0] + r, bbox[1] + r}
+	case 4:
+		return bbox
+	case 5:
+		return sort.NewOrientedBox(bbox).Enclosing()
+	}
+	return nil
+}
+
+//cross is the z component of the cross product of ab and ap
+func cross(a, b, p Point) float64 {
+	return (b.X-a.X)*(p.Y-a.Y) - (b.Y-a.Y)*(p.X-a.X)
+}
+
+//signedDistance of p to the line through a and b. It is positive on the right side looking from a to b
+func signedDistance(a, b, p Point) float64 {
+	l := math.Hypot(b.X-a.X, b.Y-a.Y)
+	if l == 0 {
+		return 0
+	}
+	return cross(a, b, p) / l
+}
+
+//segmentsIntersect tells whether segments p1p2 and q1q2 intersect
+func segmentsIntersect(p1, p2, q1, q2 Point) bool {
+	d1 := cross(q1, q2, p1)
+	d2 := cross(q1, q2, p2)
+	d3 := cross(p1, p2, q1)
+	d4 := cross(p1, p2, q2)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 >= 0 && d4 <= 0) || (d3 <= 0 && d4 >= 0))
+}
+
+//insidePolygon tells whether p is inside polygon using the even-odd rule
+func insidePolygon(polygon []Point, p Point) bool {
+	in := false
+	j := len(polygon) - 1
+	for i := 0; i < len(polygon); i++ {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+		j = i
+	}
+	return in
+}
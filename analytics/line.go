@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"fmt"
+
+	"github.com/flaviostutz/sort"
+	"github.com/sirupsen/logrus"
+)
+
+//Direction of a line crossing
+type Direction string
+
+const (
+	//In is a crossing from the left to the right side of a line, looking from A to B on the image
+	In Direction = "in"
+	//Out is a crossing from the right to the left side of a line
+	Out Direction = "out"
+)
+
+//Line is a named virtual line segment
+type Line struct {
+	Name string `json:"name" yaml:"name"`
+	A    Point  `json:"a" yaml:"a"`
+	B    Point  `json:"b" yaml:"b"`
+	//Hysteresis is the distance in pixels an object must be from the line before its side changes,
+	//so that boxes jittering over the line are not counted many times
+	Hysteresis float64 `json:"hysteresis" yaml:"hysteresis"`
+}
+
+//Crossing is emitted when a track crosses a line
+type Crossing struct {
+	Line      string    `json:"line"`
+	TrackID   int64     `json:"trackId"`
+	Direction Direction `json:"direction"`
+	Frame     int       `json:"frame"`
+	Class     string    `json:"class,omitempty"`
+}
+
+//Totals are the crossings counted on a line
+type Totals struct {
+	In  int `json:"in"`
+	Out int `json:"out"`
+}
+
+//LineCounter counts tracks crossing lines
+type LineCounter struct {
+	Lines  []Line
+	Anchor Anchor
+	//ForgetAfter is the number of frames a track may be missing before its position is forgotten
+	ForgetAfter int
+	totals      map[string]*Totals
+	//last position of each track on a side of each line
+	sides map[string]map[int64]*lineSide
+}
+
+type lineSide struct {
+	side      int
+	pos       Point
+	lastFrame int
+}
+
+//NewLineCounter creates a counter for lines using anchor to locate tracks
+func NewLineCounter(anchor Anchor, lines ...Line) (*LineCounter, error) {
+	err := anchor.Validate()
+	if err != nil {
+		return nil, err
+	}
+	c := &LineCounter{
+		Lines:       lines,
+		Anchor:      anchor,
+		ForgetAfter: 30,
+		totals:      make(map[string]*Totals),
+		sides:       make(map[string]map[int64]*lineSide),
+	}
+	for _, l := range lines {
+		if l.Name == "" {
+			return nil, fmt.Errorf("lines must have a name")
+		}
+		if _, ok := c.totals[l.Name]; ok {
+			return nil, fmt.Errorf("duplicate line %q", l.Name)
+		}
+		if l.A == l.B {
+			return nil, fmt.Errorf("line %q has no length", l.Name)
+		}
+		if l.Hysteresis < 0 {
+			return nil, fmt.Errorf("line %q hysteresis must be >= 0", l.Name)
+		}
+		c.totals[l.Name] = &Totals{}
+		c.sides[l.Name] = make(map[int64]*lineSide)
+	}
+	return c, nil
+}
+
+//Update processes the tracks of a frame and returns the crossings that happened in it
+func (c *LineCounter) Update(frame int, tracks []sort.Track) []Crossing {
+	crossings := make([]Crossing, 0)
+	for _, l := range c.Lines {
+		sides := c.sides[l.Name]
+		for _, t := range tracks {
+			p, ok := c.Anchor.Point(t.BBox)
+			if !ok {
+				continue
+			}
+			d := signedDistance(l.A, l.B, p)
+			side := 0
+			if d > l.Hysteresis {
+				side = 1
+			} else if d < -l.Hysteresis {
+				side = -1
+			}
+
+			st, ok := sides[t.ID]
+			if !ok {
+				st = &lineSide{}
+				sides[t.ID] = st
+			}
+			st.lastFrame = frame
+			if side == 0 {
+				continue
+			}
+			if st.side != 0 && side != st.side && segmentsIntersect(st.pos, p, l.A, l.B) {
+				dir := In
+				if side < 0 {
+					dir = Out
+				}
+				crossings = append(crossings, Crossing{Line: l.Name, TrackID: t.ID, Direction: dir, Frame: frame, Class: t.Class})
+				if dir == In {
+					c.totals[l.Name].In++
+				} else {
+					c.totals[l.Name].Out++
+				}
+				logrus.Debugf("Line crossed. line=%s id=%d direction=%s frame=%d", l.Name, t.ID, dir, frame)
+			}
+			st.side = side
+			st.pos = p
+		}
+		for id, st := range sides {
+			if frame-st.lastFrame > c.ForgetAfter {
+				delete(sides, id)
+			}
+		}
+	}
+	return crossings
+}
+
+//Totals returns the crossings counted so far on each line
+func (c *LineCounter) Totals() map[string]Totals {
+	r := make(map[string]Totals)
+	for name, t := range c.totals {
+		r[name] = *t
+	}
+	return r
+}
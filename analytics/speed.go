@@ -0,0 +1,86 @@
+package analytics
+
+import (
+	"fmt"
+
+	"github.com/flaviostutz/sort"
+	"github.com/sirupsen/logrus"
+)
+
+//SpeedEvent is emitted when a track goes over a speed limit
+type SpeedEvent struct {
+	TrackID int64   `json:"trackId"`
+	Frame   int     `json:"frame"`
+	Speed   float64 `json:"speed"`
+	Limit   float64 `json:"limit"`
+	Class   string  `json:"class,omitempty"`
+}
+
+//SpeedMonitor emits an event when a track stays over a speed limit for MinFrames consecutive frames.
+//Another event is only emitted for the same track after it slows down below the limit
+type SpeedMonitor struct {
+	//Limit is in meters per second when World is set (sessions WithCalibration), otherwise in pixels per frame
+	Limit     float64
+	World     bool
+	MinFrames int
+	//ForgetAfter is the number of frames a track may be missing before its state is forgotten
+	ForgetAfter int
+	tracks      map[int64]*speeding
+}
+
+type speeding struct {
+	over      int
+	alerted   bool
+	lastFrame int
+}
+
+//NewSpeedMonitor creates a monitor for limit
+func NewSpeedMonitor(limit float64, world bool, minFrames int) (*SpeedMonitor, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be > 0")
+	}
+	if minFrames < 1 {
+		return nil, fmt.Errorf("minFrames must be >= 1")
+	}
+	return &SpeedMonitor{
+		Limit:       limit,
+		World:       world,
+		MinFrames:   minFrames,
+		ForgetAfter: 30,
+		tracks:      make(map[int64]*speeding),
+	}, nil
+}
+
+//Update processes the tracks of a frame and returns the tracks that went over the limit in it
+func (m *SpeedMonitor) Update(frame int, tracks []sort.Track) []SpeedEvent {
+	events := make([]SpeedEvent, 0)
+	for _, t := range tracks {
+		speed := t.Speed
+		if m.World {
+			speed = t.WorldSpeed
+		}
+		st, ok := m.tracks[t.ID]
+		if !ok {
+			st = &speeding{}
+			m.tracks[t.ID] = st
+		}
+		st.lastFrame = frame
+		if speed <= m.Limit {
+			st.over = 0
+			st.alerted = false
+			continue
+		}
+		st.over++
+		if st.over >= m.MinFrames && !st.alerted {
+			st.alerted = true
+			events = append(events, SpeedEvent{TrackID: t.ID, Frame: frame, Speed: speed, Limit: m.Limit, Class: t.Class})
+			logrus.Debugf("Speed limit exceeded. id=%d speed=%f limit=%f", t.ID, speed, m.Limit)
+		}
+	}
+	for id, st := range m.tracks {
+		if frame-st.lastFrame > m.ForgetAfter {
+			delete(m.tracks, id)
+		}
+	}
+	return events
+}
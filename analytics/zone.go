@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"fmt"
+	gosort "sort"
+
+	"github.com/flaviostutz/sort"
+	"github.com/sirupsen/logrus"
+)
+
+//ZoneEventType is the kind of a ZoneEvent
+type ZoneEventType string
+
+const (
+	//Enter is emitted in the first frame a track is inside a zone
+	Enter ZoneEventType = "enter"
+	//Exit is emitted when a track leaves a zone or is missing for more than ForgetAfter frames
+	Exit ZoneEventType = "exit"
+	//Loitering is emitted once when a track stays in a zone longer than its LoiterAfter
+	Loitering ZoneEventType = "loitering"
+)
+
+//Zone is a named polygonal region
+type Zone struct {
+	Name    string  `json:"name" yaml:"name"`
+	Polygon []Point `json:"polygon" yaml:"polygon"`
+	//LoiterAfter is the dwell time in frames after which a loitering alert is emitted. 0 disables alerts
+	LoiterAfter int `json:"loiterAfter" yaml:"loiterAfter"`
+}
+
+//ZoneEvent is emitted when a track enters, exits or loiters in a zone
+type ZoneEvent struct {
+	Zone    string        `json:"zone"`
+	TrackID int64         `json:"trackId"`
+	Type    ZoneEventType `json:"type"`
+	Frame   int           `json:"frame"`
+	Class   string        `json:"class,omitempty"`
+	//Dwell is the number of frames the track has been in the zone
+	Dwell int `json:"dwell"`
+}
+
+//ZoneMonitor tracks the presence of tracks in zones
+type ZoneMonitor struct {
+	Zones  []Zone
+	Anchor Anchor
+	//ForgetAfter is the number of frames a track may be missing before it exits the zones it was in
+	ForgetAfter int
+	//tracks inside each zone
+	inside map[string]map[int64]*presence
+}
+
+type presence struct {
+	enterFrame int
+	lastFrame  int
+	class      string
+	alerted    bool
+}
+
+func (p *presence) dwell() int {
+	return p.lastFrame - p.enterFrame + 1
+}
+
+//NewZoneMonitor creates a monitor for zones using anchor to locate tracks
+func NewZoneMonitor(anchor Anchor, zones ...Zone) (*ZoneMonitor, error) {
+	err := anchor.Validate()
+	if err != nil {
+		return nil, err
+	}
+	m := &ZoneMonitor{
+		Zones:       zones,
+		Anchor:      anchor,
+		ForgetAfter: 30,
+		inside:      make(map[string]map[int64]*presence),
+	}
+	for _, z := range zones {
+		if z.Name == "" {
+			return nil, fmt.Errorf("zones must have a name")
+		}
+		if _, ok := m.inside[z.Name]; ok {
+			return nil, fmt.Errorf("duplicate zone %q", z.Name)
+		}
+		if len(z.Polygon) < 3 {
+			return nil, fmt.Errorf("zone %q polygon must have at least 3 points", z.Name)
+		}
+		if z.LoiterAfter < 0 {
+			return nil, fmt.Errorf("zone %q loiterAfter must be >= 0", z.Name)
+		}
+		m.inside[z.Name] = make(map[int64]*presence)
+	}
+	return m, nil
+}
+
+//Update processes the tracks of a frame and returns the zone events that happened in it
+func (m *ZoneMonitor) Update(frame int, tracks []sort.Track) []ZoneEvent {
+	events := make([]ZoneEvent, 0)
+	for _, z := range m.Zones {
+		inside := m.inside[z.Name]
+		seen := make(map[int64]bool)
+		for _, t := range tracks {
+			a, ok := m.Anchor.Point(t.BBox)
+			if !ok {
+				continue
+			}
+			seen[t.ID] = true
+			in := insidePolygon(z.Polygon, a)
+			p, was := inside[t.ID]
+			if in && !was {
+				p = &presence{enterFrame: frame, lastFrame: frame, class: t.Class}
+				inside[t.ID] = p
+				events = append(events, ZoneEvent{Zone: z.Name, TrackID: t.ID, Type: Enter, Frame: frame, Class: t.Class, Dwell: 1})
+			} else if in {
+				p.lastFrame = frame
+			} else if was {
+				delete(inside, t.ID)
+				events = append(events, ZoneEvent{Zone: z.Name, TrackID: t.ID, Type: Exit, Frame: frame, Class: t.Class, Dwell: p.dwell()})
+				continue
+			}
+			if in && z.LoiterAfter > 0 && !p.alerted && p.dwell() > z.LoiterAfter {
+				p.alerted = true
+				events = append(events, ZoneEvent{Zone: z.Name, TrackID: t.ID, Type: Loitering, Frame: frame, Class: t.Class, Dwell: p.dwell()})
+				logrus.Debugf("Loitering. zone=%s id=%d dwell=%d", z.Name, t.ID, p.dwell())
+			}
+		}
+		//tracks missing for too long are considered gone
+		ids := make([]int64, 0)
+		for id, p := range inside {
+			if !seen[id] && frame-p.lastFrame > m.ForgetAfter {
+				ids = append(ids, id)
+			}
+		}
+		gosort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			p := inside[id]
+			delete(inside, id)
+			events = append(events, ZoneEvent{Zone: z.Name, TrackID: id, Type: Exit, Frame: frame, Class: p.class, Dwell: p.dwell()})
+		}
+	}
+	return events
+}
+
+//Occupancy returns the number of tracks currently in each zone
+func (m *ZoneMonitor) Occupancy() map[string]int {
+	r := make(map[string]int)
+	for name, inside := range m.inside {
+		r[name] = len(inside)
+	}
+	return r
+}
+
+//Dwell returns the number of frames a track has been in a zone. ok is false when it isn't in the zone
+func (m *ZoneMonitor) Dwell(zone string, id int64) (int, bool) {
+	p, ok := m.inside[zone][id]
+	if !ok {
+		return 0, false
+	}
+	return p.dwell(), true
+}
@@ -0,0 +1,164 @@
+package sort
+
+import (
+	"math"
+
+	"github.com/flaviostutz/kalman"
+	"github.com/konimarti/lti"
+	"gonum.org/v1/gonum/mat"
+)
+
+//BoxModel is implemented by motion models whose boxes are not in the form [x1,y1,x2,y2]
+type BoxModel interface {
+	//BoxSize is the number of values of a box. The detection score may come right after them
+	BoxSize() int
+	//ValidBox tells whether a detection can start a tracker
+	ValidBox(bbox []float64) bool
+}
+
+//AngleModel is implemented by motion models with orientation angles in their state
+type AngleModel interface {
+	//AlignMeasurement changes the angles of z to the equivalent values closest to the state x, before an update
+	AlignMeasurement(z []float64, x mat.Vector)
+}
+
+//boxSize is the number of values of the boxes of a motion model
+func boxSize(m MotionModel) int {
+	bm, ok := m.(BoxModel)
+	if ok {
+		return bm.BoxSize()
+	}
+	return 4
+}
+
+//ConstantVelocity3D tracks 3D boxes [x,y,z,l,w,h,yaw] as AB3DMOT does. x,y,z is the box center with z up,
+//l is the length along the yaw angle (radians, around z from the x axis), w the width and h the height.
+//State is [x,y,z,yaw,l,w,h,vx,vy,vz]
+type ConstantVelocity3D struct {
+	//ProcessNoise scales the process noise covariance Q
+	ProcessNoise float64
+}
+
+//Name identifies the model in configurations
+func (m ConstantVelocity3D) Name() string {
+	return "constant-velocity-3d"
+}
+
+//System returns the discrete linear system, its noise and the initial state covariance
+func (m ConstantVelocity3D) System() (lti.Discrete, kalman.Noise, *mat.Dense) {
+	ad := identity(10)
+	for i := 0; i < 3; i++ {
+		ad.Set(i, 7+i, 1)
+	}
+	c := mat.NewDense(7, 10, nil)
+	for i := 0; i < 7; i++ {
+		c.Set(i, i, 1)
+	}
+	q := identity(10)
+	q.Scale(m.ProcessNoise, q)
+	p := identity(10)
+	p.Scale(10, p)
+	for i := 7; i < 10; i++ {
+		q.Set(i, i, 0.01*m.ProcessNoise)
+		p.Set(i, i, 10000)
+	}
+	sys := lti.Discrete{
+		Ad: ad,
+		Bd: mat.NewDense(10, 10, nil),
+		C:  c,
+		D:  mat.NewDense(7, 10, nil),
+	}
+	return sys, kalman.Noise{Q: q, R: identity(7)}, p
+}
+
+//ToMeasurement converts a box [x,y,z,l,w,h,yaw] to the measurement vector [x,y,z,yaw,l,w,h]
+func (m ConstantVelocity3D) ToMeasurement(bbox []float64) []float64 {
+	return []float64{bbox[0], bbox[1], bbox[2], bbox[6], bbox[3], bbox[4], bbox[5]}
+}
+
+//ToBox converts a state vector back to a box [x,y,z,l,w,h,yaw]
+func (m ConstantVelocity3D) ToBox(x mat.Vector) []float64 {
+	return []float64{x.AtVec(0), x.AtVec(1), x.AtVec(2), x.AtVec(4), x.AtVec(5), x.AtVec(6), wrapAngle(x.AtVec(3))}
+}
+
+//Velocity returns the box center velocity on the x,y plane per frame
+func (m ConstantVelocity3D) Velocity(x mat.Vector) []float64 {
+	return []float64{x.AtVec(7), x.AtVec(8)}
+}
+
+//World returns the position and velocity per frame of the box center on the x,y plane
+func (m ConstantVelocity3D) World(x mat.Vector) ([]float64, []float64) {
+	return []float64{x.AtVec(0), x.AtVec(1)}, []float64{x.AtVec(7), x.AtVec(8)}
+}
+
+//Constrain keeps yaw in [-pi,pi)
+func (m ConstantVelocity3D) Constrain(x *mat.VecDense) {
+	x.SetVec(3, wrapAngle(x.AtVec(3)))
+}
+
+//BoxSize is the number of values of a 3D box
+func (m ConstantVelocity3D) BoxSize() int {
+	return 7
+}
+
+//ValidBox tells whether a 3D box has a volume
+func (m ConstantVelocity3D) ValidBox(bbox []float64) bool {
+	return bbox[3] > 0 && bbox[4] > 0 && bbox[5] > 0
+}
+
+//AlignMeasurement turns the measured yaw to be less than 90 degrees away from the state, as boxes
+//are the same when rotated by 180 degrees and detectors often flip the heading
+func (m ConstantVelocity3D) AlignMeasurement(z []float64, x mat.Vector) {
+	z[3] = alignAngle(z[3], x.AtVec(3), math.Pi)
+}
+
+//alignAngle returns the angle equivalent to a, modulo period, closest to ref
+func alignAngle(a, ref, period float64) float64 {
+	d := math.Mod(a-ref, period)
+	if d > period/2 {
+		d -= period
+	} else if d < -period/2 {
+		d += period
+	}
+	return ref + d
+}
+
+//BEVIOU computes the IOU of the footprints on the x,y plane (bird's eye view) of two 3D boxes [x,y,z,l,w,h,yaw]
+func BEVIOU(box1 []float64, box2 []float64) float64 {
+	inter, a1, a2 := bevIntersection(box1, box2)
+	union := a1 + a2 - inter
+	if union <= 0 {
+		return 0
+	}
+	return inter / union
+}
+
+//IOU3D computes the volume IOU of two 3D boxes [x,y,z,l,w,h,yaw]
+func IOU3D(box1 []float64, box2 []float64) float64 {
+	inter, a1, a2 := bevIntersection(box1, box2)
+	zmin := math.Max(box1[2]-box1[5]/2, box2[2]-box2[5]/2)
+	zmax := math.Min(box1[2]+box1[5]/2, box2[2]+box2[5]/2)
+	vi := inter * math.Max(0, zmax-zmin)
+	union := a1*box1[5] + a2*box2[5] - vi
+	if union <= 0 {
+		return 0
+	}
+	return vi / union
+}
+
+//bevIntersection returns the intersection area of the footprints of two 3D boxes and their areas
+func bevIntersection(box1 []float64, box2 []float64) (float64, float64, float64) {
+	p1 := rectCorners(box1[0], box1[1], box1[3], box1[4], box1[6])
+	p2 := rectCorners(box2[0], box2[1], box2[3], box2[4], box2[6])
+	a1, a2 := box1[3]*box1[4], box2[3]*box2[4]
+	//far apart boxes don't need clipping
+	r1, r2 := math.Hypot(box1[3], box1[4])/2, math.Hypot(box2[3], box2[4])/2
+	if math.Hypot(box1[0]-box2[0], box1[1]-box2[1]) >= r1+r2 {
+		return 0, a1, a2
+	}
+	inter := clipPolygon(p1, p2)
+	if len(inter) < 3 {
+		return 0, a1, a2
+	}
+	return polygonArea(inter), a1, a2
+}
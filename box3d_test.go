@@ -0,0 +1,75 @@
+package sort
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIOU3D(t *testing.T) {
+	a := []float64{0, 0, 0, 4, 2, 2, 0}
+	if math.Abs(IOU3D(a, a)-1) > 1e-9 || math.Abs(BEVIOU(a, a)-1) > 1e-9 {
+		t.Errorf("same boxes should have IOU 1")
+	}
+	//half the length apart
+	b := []float64{2, 0, 1, 4, 2, 2, 0}
+	if math.Abs(BEVIOU(a, b)-1.0/3) > 1e-9 {
+		t.Errorf("unexpected bev iou %f", BEVIOU(a, b))
+	}
+	//half the footprint and half the height
+	if math.Abs(IOU3D(a, b)-2.0/14) > 1e-9 {
+		t.Errorf("unexpected 3d iou %f", IOU3D(a, b))
+	}
+	//a square rotated by 45 degrees overlaps 2*(sqrt(2)-1) of its area
+	sq := []float64{0, 0, 0, 2, 2, 1, 0}
+	rot := []float64{0, 0, 0, 2, 2, 1, math.Pi / 4}
+	inter := 8 * (math.Sqrt2 - 1)
+	if math.Abs(BEVIOU(sq, rot)-inter/(8-inter)) > 1e-9 {
+		t.Errorf("unexpected rotated iou %f", BEVIOU(sq, rot))
+	}
+	//flipped heading is the same box
+	if math.Abs(IOU3D(a, []float64{0, 0, 0, 4, 2, 2, math.Pi})-1) > 1e-9 {
+		t.Errorf("flipped box should have IOU 1")
+	}
+	if IOU3D(a, []float64{10, 0, 0, 4, 2, 2, 0}) != 0 {
+		t.Errorf("far boxes should have IOU 0")
+	}
+
+	if math.Abs(alignAngle(3.1, -3.1, math.Pi)-(-3.1-(2*math.Pi-6.2))) > 1e-9 {
+		t.Errorf("unexpected aligned angle %f", alignAngle(3.1, -3.1, math.Pi))
+	}
+	if math.Abs(alignAngle(math.Pi+0.1, 0, math.Pi)-0.1) > 1e-9 {
+		t.Errorf("flipped angles should be aligned")
+	}
+	if math.Abs(wrapAngle(3*math.Pi/2)+math.Pi/2) > 1e-9 {
+		t.Errorf("unexpected wrapped angle %f", wrapAngle(3*math.Pi/2))
+	}
+}
+
+func TestBox3DCostFunctions(t *testing.T) {
+	s, err := NewSORT(WithMotionModel("constant-velocity-3d"), WithCostFunction("iou-3d"), WithMinUpdatesUsePrediction(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := 0; f < 10; f++ {
+		x := 0.5 * float64(f)
+		err = s.Update([][]float64{{x, 0, 0, 4, 2, 2, 0, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.Trackers) != 1 || len(s.Tracks()) != 1 {
+		t.Errorf("3d box should be tracked. trackers=%d", len(s.Trackers))
+	}
+
+	//box sizes of the model and cost function must agree
+	for _, mc := range [][]string{{"constant-velocity", "iou-3d"}, {"constant-velocity", "bev-iou"}, {"constant-velocity-3d", "iou"}, {"constant-velocity-3d", "giou"}} {
+		_, err = NewSORT(WithMotionModel(mc[0]), WithCostFunction(mc[1]))
+		if err == nil {
+			t.Errorf("cost function %s should be rejected with motion model %s", mc[1], mc[0])
+		}
+	}
+	_, err = NewSORT(WithMotionModel("constant-velocity-3d"), WithCostFunction("bev-iou"))
+	if err != nil {
+		t.Errorf("bev-iou should be accepted with 3d boxes. err=%s", err)
+	}
+}
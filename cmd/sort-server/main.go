@@ -0,0 +1,41 @@
+//Command sort-server exposes SORT tracking sessions through a REST API so that non Go services can use the tracker
+//
+//    sort-server --listen :8080 --idle-timeout 10m
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "HTTP listen address")
+	idleTimeout := flag.Duration("idle-timeout", 10*time.Minute, "Sessions not used for this long are removed")
+	maxBodyBytes := flag.Int64("max-body-bytes", 10<<20, "Requests with larger bodies are rejected")
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warning or error")
+	flag.Parse()
+
+	level, err := logrus.ParseLevel(*logLevel)
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+	logrus.SetLevel(level)
+	if *idleTimeout <= 0 {
+		logrus.Fatalf("idle-timeout must be > 0")
+	}
+	if *maxBodyBytes <= 0 {
+		logrus.Fatalf("max-body-bytes must be > 0")
+	}
+
+	s := newServer(*idleTimeout, *maxBodyBytes)
+	go s.runEviction(make(chan struct{}))
+
+	logrus.Infof("Listening on %s", *listen)
+	err = http.ListenAndServe(*listen, s)
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+}
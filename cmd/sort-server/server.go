@@ -0,0 +1,308 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/stream"
+	"github.com/sirupsen/logrus"
+)
+
+//maxForecastSteps limits the work done by a forecast request
+const maxForecastSteps = 300
+
+//server exposes SORT sessions over HTTP
+//
+//    POST   /sessions                  create a session. Body is an optional sort.Config
+//    GET    /sessions                  list session IDs
+//    GET    /sessions/{id}             session info
+//    DELETE /sessions/{id}             remove a session
+//    POST   /sessions/{id}/frames      track a stream.Frame and return a stream.TrackedFrame
+//    GET    /sessions/{id}/tracks      tracks of the last frame
+//    GET    /sessions/{id}/forecast    positions of the tracks in the next ?steps=N frames, by track ID
+//    GET    /sessions/{id}/snapshot    session state as sort.Snapshot
+//    PUT    /sessions/{id}/snapshot    create or replace a session from a sort.Snapshot
+type server struct {
+	mu          sync.RWMutex
+	sessions    map[string]*session
+	idleTimeout time.Duration
+	//maxBodyBytes limits the size of request bodies
+	maxBodyBytes int64
+}
+
+//session serializes requests to a SORT instance, which is not safe for concurrent use
+type session struct {
+	mu       sync.Mutex
+	sort     *sort.SORT
+	lastUsed time.Time
+}
+
+type sessionInfo struct {
+	ID         string      `json:"id"`
+	Config     sort.Config `json:"config"`
+	FrameCount int         `json:"frameCount"`
+	Trackers   int         `json:"trackers"`
+}
+
+func newServer(idleTimeout time.Duration, maxBodyBytes int64) *server {
+	return &server{
+		sessions:     make(map[string]*session),
+		idleTimeout:  idleTimeout,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "sessions" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("not found"))
+		return
+	}
+	logrus.Debugf("%s %s", r.Method, r.URL.Path)
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodPost:
+			s.createSession(w, r)
+		case http.MethodGet:
+			s.listSessions(w)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+		}
+		return
+	}
+
+	id := parts[1]
+	resource := ""
+	if len(parts) == 3 {
+		resource = parts[2]
+	}
+	if resource == "snapshot" && r.Method == http.MethodPut {
+		s.restoreSession(w, r, id)
+		return
+	}
+	if resource == "" && r.Method == http.MethodDelete {
+		s.deleteSession(w, id)
+		return
+	}
+
+	ss := s.session(id)
+	if ss == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session %s not found", id))
+		return
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	//the session may have been evicted or replaced while waiting for its lock
+	s.mu.RLock()
+	current := s.sessions[id] == ss
+	s.mu.RUnlock()
+	if !current {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session %s not found", id))
+		return
+	}
+	ss.lastUsed = time.Now()
+
+	switch {
+	case resource == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, info(id, ss.sort))
+	case resource == "frames" && r.Method == http.MethodPost:
+		f := stream.Frame{}
+		err := decodeBody(r, &f)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		err = ss.sort.UpdateDetections(f.Detections)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, stream.TrackedFrame{Frame: f.Frame, Timestamp: f.Timestamp, Stream: f.Stream, Tracks: ss.sort.Tracks()})
+	case resource == "tracks" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, ss.sort.Tracks())
+	case resource == "forecast" && r.Method == http.MethodGet:
+		steps := 1
+		if v := r.URL.Query().Get("steps"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxForecastSteps {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("steps must be between 1 and %d", maxForecastSteps))
+				return
+			}
+			steps = n
+		}
+		writeJSON(w, http.StatusOK, ss.sort.Forecast(steps))
+	case resource == "snapshot" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, ss.sort.Snapshot())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+	}
+}
+
+func (s *server) createSession(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cfg, err := sort.ParseConfig(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	st, err := sort.NewSORT(sort.WithConfig(cfg))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.mu.Lock()
+	s.sessions[id] = &session{sort: st, lastUsed: time.Now()}
+	s.mu.Unlock()
+	logrus.Infof("Session created. id=%s", id)
+	writeJSON(w, http.StatusCreated, info(id, st))
+}
+
+func (s *server) restoreSession(w http.ResponseWriter, r *http.Request, id string) {
+	snap := sort.Snapshot{}
+	err := decodeBody(r, &snap)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	err = snap.Config.Validate()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	st, err := sort.Restore(snap)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.mu.Lock()
+	s.sessions[id] = &session{sort: st, lastUsed: time.Now()}
+	s.mu.Unlock()
+	logrus.Infof("Session restored. id=%s", id)
+	writeJSON(w, http.StatusOK, info(id, st))
+}
+
+func (s *server) deleteSession(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session %s not found", id))
+		return
+	}
+	logrus.Infof("Session deleted. id=%s", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) listSessions(w http.ResponseWriter) {
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	s.mu.RUnlock()
+	writeJSON(w, http.StatusOK, ids)
+}
+
+func (s *server) session(id string) *session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sessions[id]
+}
+
+//evictIdle removes sessions not used for longer than the idle timeout
+func (s *server) evictIdle(now time.Time) {
+	//sessions are checked without holding the server lock, so a slow update only delays its own session
+	s.mu.RLock()
+	sessions := make(map[string]*session, len(s.sessions))
+	for id, ss := range s.sessions {
+		sessions[id] = ss
+	}
+	s.mu.RUnlock()
+	for id, ss := range sessions {
+		ss.mu.Lock()
+		idle := now.Sub(ss.lastUsed)
+		if idle > s.idleTimeout {
+			s.mu.Lock()
+			//the session may have been deleted or replaced meanwhile
+			if s.sessions[id] == ss {
+				delete(s.sessions, id)
+				logrus.Infof("Idle session evicted. id=%s idle=%s", id, idle)
+			}
+			s.mu.Unlock()
+		}
+		ss.mu.Unlock()
+	}
+}
+
+//runEviction checks for idle sessions until stop is closed
+func (s *server) runEviction(stop <-chan struct{}) {
+	interval := s.idleTimeout / 2
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			s.evictIdle(now)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func info(id string, st *sort.SORT) sessionInfo {
+	return sessionInfo{ID: id, Config: st.Config(), FrameCount: st.FrameCount, Trackers: len(st.Trackers)}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("invalid body. err=%s", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		logrus.Warnf("Error writing response. err=%s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/stream"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	s := newServer(time.Minute, 1<<20)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := sessionInfo{}
+	status := call(t, ts, http.MethodPost, "/sessions", `{"preset":"pedestrian","minUpdatesUsePrediction":1}`, &created)
+	if status != http.StatusCreated || created.ID == "" || created.Config.MinUpdatesUsePrediction != 1 || created.Config.MaxPredictsWithoutUpdate != 5 {
+		t.Fatalf("Unexpected session creation. status=%d info=%+v", status, created)
+	}
+	status = call(t, ts, http.MethodPost, "/sessions", `{"iouThreshold":3}`, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("Invalid config should be rejected. status=%d", status)
+	}
+
+	path := "/sessions/" + created.ID
+	tf := stream.TrackedFrame{}
+	for i := 1; i <= 3; i++ {
+		status = call(t, ts, http.MethodPost, path+"/frames", `{"frame":1,"detections":[{"bbox":[10,10,30,50],"class":"person"}]}`, &tf)
+		if status != http.StatusOK {
+			t.Fatalf("Error posting frame. status=%d", status)
+		}
+	}
+	if len(tf.Tracks) != 1 || tf.Tracks[0].Class != "person" {
+		t.Errorf("Unexpected tracked frame %+v", tf)
+	}
+	tracks := []sort.Track{}
+	call(t, ts, http.MethodGet, path+"/tracks", "", &tracks)
+	if len(tracks) != 1 || tracks[0].ID != tf.Tracks[0].ID {
+		t.Errorf("Unexpected tracks %+v", tracks)
+	}
+
+	forecast := map[string][]sort.Forecast{}
+	call(t, ts, http.MethodGet, path+"/forecast?steps=5", "", &forecast)
+	if len(forecast) != 1 || len(forecast[fmt.Sprint(tracks[0].ID)]) != 5 {
+		t.Errorf("Unexpected forecast %+v", forecast)
+	}
+
+	snap := sort.Snapshot{}
+	status = call(t, ts, http.MethodGet, path+"/snapshot", "", &snap)
+	if status != http.StatusOK || snap.FrameCount != 3 || len(snap.Trackers) != 1 {
+		t.Fatalf("Unexpected snapshot. status=%d snapshot=%+v", status, snap)
+	}
+	body, _ := json.Marshal(snap)
+	restored := sessionInfo{}
+	status = call(t, ts, http.MethodPut, "/sessions/copy/snapshot", string(body), &restored)
+	if status != http.StatusOK || restored.FrameCount != 3 || restored.Trackers != 1 {
+		t.Errorf("Unexpected restore. status=%d info=%+v", status, restored)
+	}
+	call(t, ts, http.MethodGet, "/sessions/copy/tracks", "", &tracks)
+	if len(tracks) != 1 || tracks[0].ID != tf.Tracks[0].ID {
+		t.Errorf("Restored session should report the same tracks %+v", tracks)
+	}
+
+	status = call(t, ts, http.MethodDelete, path, "", nil)
+	if status != http.StatusNoContent {
+		t.Errorf("Unexpected delete status %d", status)
+	}
+	status = call(t, ts, http.MethodGet, path+"/tracks", "", nil)
+	if status != http.StatusNotFound {
+		t.Errorf("Deleted session should not be found. status=%d", status)
+	}
+}
+
+func TestEvictIdle(t *testing.T) {
+	s := newServer(time.Minute, 1<<20)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := sessionInfo{}
+	call(t, ts, http.MethodPost, "/sessions", "", &created)
+	s.evictIdle(time.Now())
+	if s.session(created.ID) == nil {
+		t.Errorf("Session shouldn't be evicted yet")
+	}
+	s.evictIdle(time.Now().Add(2 * time.Minute))
+	if s.session(created.ID) != nil {
+		t.Errorf("Idle session should be evicted")
+	}
+
+	//a session busy in an update must not block the others during eviction
+	busy, other := sessionInfo{}, sessionInfo{}
+	call(t, ts, http.MethodPost, "/sessions", "", &busy)
+	call(t, ts, http.MethodPost, "/sessions", "", &other)
+	ss := s.session(busy.ID)
+	ss.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		s.evictIdle(time.Now())
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	status := call(t, ts, http.MethodGet, "/sessions/"+other.ID, "", nil)
+	if status != http.StatusOK {
+		t.Errorf("Unexpected status %d", status)
+	}
+	ss.mu.Unlock()
+	<-done
+}
+
+func call(t *testing.T, ts *httptest.Server, method string, path string, body string, out interface{}) int {
+	req, err := http.NewRequest(method, ts.URL+path, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("Error creating request. err=%s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error calling %s %s. err=%s", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		err = json.NewDecoder(resp.Body).Decode(out)
+		if err != nil {
+			t.Fatalf("Error decoding response. err=%s", err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestEvictedWhileWaiting(t *testing.T) {
+	s := newServer(time.Minute, 1<<20)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	//a request waiting for the lock of a session that is evicted meanwhile must not use it
+	created := sessionInfo{}
+	call(t, ts, http.MethodPost, "/sessions", "", &created)
+	ss := s.session(created.ID)
+	ss.mu.Lock()
+	status := make(chan int)
+	go func() {
+		status <- call(t, ts, http.MethodGet, "/sessions/"+created.ID, "", nil)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	s.mu.Lock()
+	delete(s.sessions, created.ID)
+	s.mu.Unlock()
+	ss.mu.Unlock()
+	if st := <-status; st != http.StatusNotFound {
+		t.Errorf("Evicted session should not be found. status=%d", st)
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	s := newServer(time.Minute, 64)
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	created := sessionInfo{}
+	call(t, ts, http.MethodPost, "/sessions", "", &created)
+	body := fmt.Sprintf(`{"frame":1,"detections":[{"bbox":[10,10,30,50],"class":"%s"}]}`, strings.Repeat("a", 100))
+	status := call(t, ts, http.MethodPost, "/sessions/"+created.ID+"/frames", body, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("Large bodies should be rejected. status=%d", status)
+	}
+	status = call(t, ts, http.MethodPost, "/sessions", `{"preset":"pedestrian","minUpdatesUsePrediction":1,"iouThreshold":0.3}`, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("Large configs should be rejected. status=%d", status)
+	}
+}
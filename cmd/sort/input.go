@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/mot"
+	"github.com/flaviostutz/sort/stream"
+)
+
+//frameReader returns detections frame by frame. Returns io.EOF when there are no more frames
+type frameReader interface {
+	Next() (int, []sort.Detection, error)
+}
+
+//newFrameReader creates a reader of detections with boxSize values, the size of the boxes of the session motion model
+func newFrameReader(format string, r io.Reader, minScore float64, boxSize int) (frameReader, error) {
+	switch format {
+	case "mot":
+		if boxSize != 4 {
+			return nil, fmt.Errorf("input format mot only has 4 value boxes. use jsonl or csv for boxes with %d values", boxSize)
+		}
+		rows, err := mot.Read(r)
+		if err != nil {
+			return nil, err
+		}
+		return newRowsReader(rows, minScore), nil
+	case "csv":
+		rows, err := readCSV(r, boxSize)
+		if err != nil {
+			return nil, err
+		}
+		return newRowsReader(rows, minScore), nil
+	case "jsonl":
+		return &jsonReader{scanner: newScanner(r), minScore: minScore, boxSize: boxSize}, nil
+	}
+	return nil, fmt.Errorf("unknown input format %q", format)
+}
+
+//rowsReader iterates over all frames of a file, including frames without detections
+type rowsReader struct {
+	frames   [][]mot.Row
+	next     int
+	minScore float64
+}
+
+func newRowsReader(rows []mot.Row, minScore float64) *rowsReader {
+	return &rowsReader{frames: mot.GroupByFrame(rows), minScore: minScore}
+}
+
+func (r *rowsReader) Next() (int, []sort.Detection, error) {
+	if r.next >= len(r.frames) {
+		return 0, nil, io.EOF
+	}
+	dets := make([]sort.Detection, 0)
+	for _, row := range r.frames[r.next] {
+		if row.Conf < r.minScore {
+			continue
+		}
+		dets = append(dets, sort.Detection{BBox: row.BBox, Score: row.Conf})
+	}
+	r.next = r.next + 1
+	return r.next, dets, nil
+}
+
+//readCSV reads lines in the form frame,x1,y1,x2,y2[,score], or with the n values of other boxes in place
+//of x1,y1,x2,y2. See boxColumns. A header line is ignored
+func readCSV(r io.Reader, n int) ([]mot.Row, error) {
+	rows := make([]mot.Row, 0)
+	scanner := newScanner(r)
+	ln := 0
+	for scanner.Scan() {
+		ln = ln + 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 1+n {
+			return nil, fmt.Errorf("line %d: expected frame,%s[,score]", ln, boxColumns(n))
+		}
+		v := make([]float64, len(fields))
+		for i, f := range fields {
+			n, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+			if err != nil {
+				if ln == 1 {
+					v = nil
+					break
+				}
+				return nil, fmt.Errorf("line %d: invalid field %q", ln, f)
+			}
+			v[i] = n
+		}
+		if v == nil {
+			continue
+		}
+		row := mot.Row{Frame: int(v[0]), ID: -1, BBox: v[1 : 1+n], Conf: 1, Class: -1, Visibility: -1}
+		if len(v) > 1+n {
+			row.Conf = v[1+n]
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+//jsonReader reads one stream.Frame per line. Missing frame numbers are returned as frames without detections
+type jsonReader struct {
+	scanner  *bufio.Scanner
+	last     int
+	pending  *stream.Frame
+	minScore float64
+	boxSize  int
+	ln       int
+}
+
+func (r *jsonReader) Next() (int, []sort.Detection, error) {
+	if r.pending == nil {
+		f, err := r.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		if f.Frame == 0 {
+			f.Frame = r.last + 1
+		}
+		if f.Frame <= r.last {
+			return 0, nil, fmt.Errorf("line %d: frame %d is not after frame %d", r.ln, f.Frame, r.last)
+		}
+		r.pending = &f
+	}
+	r.last = r.last + 1
+	if r.pending.Frame > r.last {
+		return r.last, []sort.Detection{}, nil
+	}
+	dets := make([]sort.Detection, 0)
+	for _, d := range r.pending.Detections {
+		if d.Score < r.minScore {
+			continue
+		}
+		dets = append(dets, d)
+	}
+	r.pending = nil
+	return r.last, dets, nil
+}
+
+func (r *jsonReader) readFrame() (stream.Frame, error) {
+	for r.scanner.Scan() {
+		r.ln = r.ln + 1
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		f := stream.Frame{}
+		err := json.Unmarshal([]byte(line), &f)
+		if err != nil {
+			return stream.Frame{}, fmt.Errorf("line %d: %s", r.ln, err)
+		}
+		for _, d := range f.Detections {
+			//points may omit the radius
+			if len(d.BBox) != r.boxSize && !(r.boxSize == 3 && len(d.BBox) == 2) {
+				return stream.Frame{}, fmt.Errorf("line %d: bbox should contain %d positions: %s", r.ln, r.boxSize, boxColumns(r.boxSize))
+			}
+		}
+		return f, nil
+	}
+	err := r.scanner.Err()
+	if err == nil {
+		err = io.EOF
+	}
+	return stream.Frame{}, err
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return scanner
+}
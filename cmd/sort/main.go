@@ -0,0 +1,273 @@
+//Command sort tracks objects offline from detection files
+//
+//    sort --input det.txt --input-format mot --output tracks.txt --preset pedestrian --eval gt.txt
+//
+//With --stream it reads JSON lines with frames of multiple streams from stdin and writes tracks to stdout as they are processed
+//
+//    detector.py | sort --stream --preset vehicle
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/eval"
+	"github.com/flaviostutz/sort/mot"
+	"github.com/flaviostutz/sort/offline"
+	"github.com/flaviostutz/sort/stream"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if err == flag.ErrHelp {
+		os.Exit(2)
+	}
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	def := sort.DefaultConfig()
+	fs := flag.NewFlagSet("sort", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	input := fs.String("input", "-", "Detections file. '-' reads from stdin")
+	inputFormat := fs.String("input-format", "mot", "Detections format: mot, jsonl or csv")
+	output := fs.String("output", "-", "Tracks file. '-' writes to stdout")
+	outputFormat := fs.String("output-format", "mot", "Tracks format: mot, jsonl or csv")
+	configFile := fs.String("config", "", "YAML or JSON file with tracker parameters")
+	preset := fs.String("preset", "", "Tracker parameters preset: pedestrian, vehicle, drone or point")
+	maxPredicts := fs.Int("max-predicts-without-update", def.MaxPredictsWithoutUpdate, "Frames a tracker survives without matching a detection")
+	minUpdates := fs.Int("min-updates-use-prediction", def.MinUpdatesUsePrediction, "Updates before a tracker uses its prediction and is reported")
+	iouThreshold := fs.Float64("iou-threshold", def.IOUThreshold, "Minimum score for matching a detection to a tracker")
+	motionModel := fs.String("motion-model", def.MotionModel, "Kalman motion model: constant-velocity, constant-position, constant-velocity-3d, constant-velocity-rotated, constant-velocity-point or ground-plane (needs a calibration in --config). Models without 4 value boxes need jsonl or csv files")
+	processNoise := fs.Float64("process-noise", def.ProcessNoise, "Process noise scale of the motion model")
+	costFunction := fs.String("cost-function", def.CostFunction, "Detection x tracker score: iou, giou, iou-3d, bev-iou, rotated-iou, euclidean or mahalanobis (need --max-point-distance)")
+	confidenceDecay := fs.Float64("confidence-decay", def.ConfidenceDecay, "Fraction of the track confidence lost for each frame without a detection")
+	minConfidence := fs.Float64("min-confidence", 0, "Remove coasting trackers below this confidence instead of using max-predicts-without-update")
+	reportConfidence := fs.Float64("report-confidence", 0, "Report tracks with at least this confidence instead of using min-updates-use-prediction")
+	velocitySmoothing := fs.Float64("velocity-smoothing", 0, "Weight of the previous value in the smoothing of track velocities")
+	maxDistance := fs.Float64("max-distance", 0, "Don't match detections and trackers farther apart on the ground plane. Needs a calibration in --config")
+	maxPointDistance := fs.Float64("max-point-distance", 0, "Don't match detections and trackers farther apart with the euclidean (pixels) or mahalanobis (standard deviations) costs")
+	scoreNoise := fs.String("score-noise", "", "Scale the measurement noise with the detection score: nsa (1-score) or inverse (1/score)")
+	frameWidth := fs.Float64("frame-width", 0, "Image width in pixels. Enables clipping and removal of tracks leaving the frame")
+	frameHeight := fs.Float64("frame-height", 0, "Image height in pixels")
+	//DPM detections of MOT17 have negative scores, so nothing is dropped by default
+	minScore := fs.Float64("min-score", math.Inf(-1), "Ignore detections with score below this value. All detections are used by default")
+	smooth := fs.Bool("smooth", false, "Refine track boxes with a Rauch-Tung-Striebel smoother after all frames are tracked")
+	stitch := fs.Int("stitch", 0, "Merge tracklets separated by up to this many frames after tracking. 0 disables it")
+	interpolate := fs.Int("interpolate", 0, "Fill track gaps of up to this many frames after tracking. 0 disables it")
+	interpolation := fs.String("interpolation", "linear", "Gap filling method: linear or kalman")
+	evalFile := fs.String("eval", "", "MOTChallenge gt.txt file. Prints tracking metrics at the end")
+	streamMode := fs.Bool("stream", false, "Read stream.Frame JSON lines from stdin and write stream.TrackedFrame JSON lines to stdout as frames arrive")
+	queueSize := fs.Int("queue-size", 16, "Frames read ahead of tracking in --stream mode")
+	logLevel := fs.String("log-level", "info", "Log level: debug, info, warning or error")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	level, err := logrus.ParseLevel(*logLevel)
+	if err != nil {
+		return err
+	}
+	logrus.SetLevel(level)
+
+	cfg := def
+	if *preset != "" && *configFile != "" {
+		return fmt.Errorf("use either --preset or --config")
+	}
+	if *preset != "" {
+		cfg, err = sort.Preset(*preset)
+		if err != nil {
+			return err
+		}
+	}
+	if *configFile != "" {
+		cfg, err = sort.LoadConfig(*configFile)
+		if err != nil {
+			return err
+		}
+	}
+	//explicit flags override config files and presets
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "max-predicts-without-update":
+			cfg.MaxPredictsWithoutUpdate = *maxPredicts
+		case "min-updates-use-prediction":
+			cfg.MinUpdatesUsePrediction = *minUpdates
+		case "iou-threshold":
+			cfg.IOUThreshold = *iouThreshold
+		case "motion-model":
+			cfg.MotionModel = *motionModel
+		case "process-noise":
+			cfg.ProcessNoise = *processNoise
+		case "cost-function":
+			cfg.CostFunction = *costFunction
+		case "confidence-decay":
+			cfg.ConfidenceDecay = *confidenceDecay
+		case "min-confidence":
+			cfg.MinConfidence = *minConfidence
+		case "report-confidence":
+			cfg.ReportConfidence = *reportConfidence
+		case "velocity-smoothing":
+			cfg.VelocitySmoothing = *velocitySmoothing
+		case "max-distance":
+			cfg.MaxDistance = *maxDistance
+		case "max-point-distance":
+			cfg.MaxPointDistance = *maxPointDistance
+		case "score-noise":
+			cfg.ScoreNoise = *scoreNoise
+		case "frame-width":
+			cfg.FrameWidth = *frameWidth
+		case "frame-height":
+			cfg.FrameHeight = *frameHeight
+		}
+	})
+	logrus.Debugf("Tracker config %+v", cfg)
+
+	if *streamMode {
+		p, err := stream.NewProcessor(*queueSize, sort.WithConfig(cfg))
+		if err != nil {
+			return err
+		}
+		return p.Run(context.Background(), stdin, stdout)
+	}
+
+	opts := []sort.Option{sort.WithConfig(cfg)}
+	if *smooth {
+		opts = append(opts, sort.WithHistory())
+	}
+	s, err := sort.NewSORT(opts...)
+	if err != nil {
+		return err
+	}
+	if *evalFile != "" && s.BoxSize() != 4 {
+		return fmt.Errorf("--eval needs a motion model with 4 value boxes")
+	}
+
+	in := stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	out := stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	reader, err := newFrameReader(*inputFormat, in, *minScore, s.BoxSize())
+	if err != nil {
+		return err
+	}
+	writer, err := newTrackWriter(*outputFormat, out, s.BoxSize())
+	if err != nil {
+		return err
+	}
+
+	//with post processing, tracks are only written after all frames are tracked
+	post := *smooth || *stitch > 0 || *interpolate > 0
+	frames := make([]offline.Frame, 0)
+	for {
+		frame, dets, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		err = s.UpdateDetections(dets)
+		if err != nil {
+			return err
+		}
+		tracks := s.Tracks()
+		if post {
+			frames = append(frames, offline.Frame{Frame: frame, Tracks: tracks})
+			continue
+		}
+		err = writer.WriteTracks(frame, tracks)
+		if err != nil {
+			return err
+		}
+		if *evalFile != "" {
+			frames = append(frames, offline.Frame{Frame: frame, Tracks: tracks})
+		}
+	}
+	if *smooth {
+		frames, err = offline.Smooth(s, frames)
+		if err != nil {
+			return err
+		}
+	}
+	if *stitch > 0 {
+		sc := offline.DefaultStitchConfig()
+		sc.MaxGap = *stitch
+		remap, err := offline.Stitch(frames, nil, sc)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("Stitched %d tracklets", len(remap))
+		frames = offline.Remap(frames, remap)
+	}
+	if *interpolate > 0 {
+		frames, err = offline.Interpolate(frames, *interpolate, *interpolation)
+		if err != nil {
+			return err
+		}
+	}
+	if post {
+		for _, f := range frames {
+			err = writer.WriteTracks(f.Frame, f.Tracks)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = writer.Flush()
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Tracked %d frames", s.FrameCount)
+
+	if *evalFile != "" {
+		gt, err := mot.ReadFile(*evalFile)
+		if err != nil {
+			return err
+		}
+		results := make([]mot.Row, 0)
+		for _, f := range frames {
+			for _, t := range f.Tracks {
+				results = append(results, mot.Row{Frame: f.Frame, ID: t.ID, BBox: t.BBox, Conf: 1})
+			}
+		}
+		printMetrics(stderr, eval.Evaluate(gt, results))
+	}
+	return nil
+}
+
+func printMetrics(w io.Writer, r eval.Result) {
+	c := r.CLEAR
+	fmt.Fprintf(w, "CLEAR     MOTA=%.3f MOTP=%.3f TP=%d FP=%d FN=%d IDSW=%d Frag=%d MT=%d PT=%d ML=%d\n",
+		c.MOTA, c.MOTP, c.TP, c.FP, c.FN, c.IDSW, c.Frag, c.MT, c.PT, c.ML)
+	i := r.Identity
+	fmt.Fprintf(w, "Identity  IDF1=%.3f IDP=%.3f IDR=%.3f IDTP=%d IDFP=%d IDFN=%d\n",
+		i.IDF1, i.IDP, i.IDR, i.IDTP, i.IDFP, i.IDFN)
+	h := r.HOTA
+	fmt.Fprintf(w, "HOTA      HOTA=%.3f DetA=%.3f AssA=%.3f LocA=%.3f DetRe=%.3f DetPr=%.3f AssRe=%.3f AssPr=%.3f\n",
+		h.HOTA, h.DetA, h.AssA, h.LocA, h.DetRe, h.DetPr, h.AssRe, h.AssPr)
+}
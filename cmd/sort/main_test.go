@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunJSONLines(t *testing.T) {
+	in := `{"frame":1,"detections":[{"bbox":[10,10,30,50],"score":0.9},{"bbox":[100,100,120,140],"score":0.1}]}
+{"frame":3,"detections":[{"bbox":[11,10,31,50]}]}
+`
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	err := run([]string{"--input-format", "jsonl", "--output-format", "csv", "--min-score", "0.5"}, strings.NewReader(in), &out, &errOut)
+	if err != nil {
+		t.Fatalf("Error running sort. err=%s", err)
+	}
+	expected := "frame,id,x1,y1,x2,y2,score\n"
+	if !strings.HasPrefix(out.String(), expected) || strings.Count(out.String(), "\n") != 3 || !strings.Contains(out.String(), "\n3,") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestRunEval(t *testing.T) {
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	err := run([]string{"--input", "../../eval/testdata/tracker.txt", "--preset", "pedestrian", "--eval", "../../eval/testdata/gt.txt"}, nil, &out, &errOut)
+	if err != nil {
+		t.Fatalf("Error running sort. err=%s", err)
+	}
+	if !strings.Contains(errOut.String(), "MOTA=") || !strings.Contains(errOut.String(), "IDF1=") || !strings.Contains(errOut.String(), "HOTA=") {
+		t.Errorf("Metrics not printed. stderr=%q", errOut.String())
+	}
+
+	err = run([]string{"--iou-threshold", "2"}, strings.NewReader(""), &out, &errOut)
+	if err == nil {
+		t.Errorf("Invalid parameters should be rejected")
+	}
+}
+
+func TestRunInterpolate(t *testing.T) {
+	in := "1,-1,10,10,20,40,0.9\n2,-1,12,10,20,40,0.9\n3,-1,14,10,20,40,0.9\n6,-1,20,10,20,40,0.9\n"
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	err := run([]string{"--output-format", "csv", "--max-predicts-without-update", "3", "--min-updates-use-prediction", "1", "--interpolate", "5"}, strings.NewReader(in), &out, &errOut)
+	if err != nil {
+		t.Fatalf("Error running sort. err=%s", err)
+	}
+	if !strings.Contains(out.String(), "\n4,") || !strings.Contains(out.String(), "\n5,") {
+		t.Errorf("Gap not filled %q", out.String())
+	}
+}
+
+func TestRun3DBoxes(t *testing.T) {
+	in := `{"frame":1,"detections":[{"bbox":[0,0,0,4,2,2,0.1],"score":0.9}]}
+{"frame":2,"detections":[{"bbox":[0.5,0,0,4,2,2,0.1],"score":0.9}]}
+{"frame":4,"detections":[{"bbox":[1.5,0,0,4,2,2,0.1],"score":0.9}]}
+`
+	args := []string{"--input-format", "jsonl", "--output-format", "csv", "--motion-model", "constant-velocity-3d", "--cost-function", "iou-3d",
+		"--min-updates-use-prediction", "1", "--max-predicts-without-update", "2", "--interpolate", "2"}
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	err := run(args, strings.NewReader(in), &out, &errOut)
+	if err != nil {
+		t.Fatalf("Error running sort. err=%s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 || lines[0] != "frame,id,x,y,z,l,w,h,yaw,score" || !strings.HasPrefix(lines[3], "3,") || strings.Count(lines[3], ",") != 9 {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	err = run(append(args, "--output-format", "mot"), strings.NewReader(in), &out, &errOut)
+	if err == nil {
+		t.Errorf("mot output should be rejected for 3d boxes")
+	}
+	err = run(args, strings.NewReader(`{"frame":1,"detections":[{"bbox":[10,10,30,50]}]}`), &out, &errOut)
+	if err == nil {
+		t.Errorf("4 value boxes should be rejected for 3d boxes")
+	}
+}
+
+func TestRunPoints(t *testing.T) {
+	//the radius may be omitted
+	in := `{"frame":1,"detections":[{"bbox":[100,100],"score":0.9},{"bbox":[400,300,5],"score":0.8}]}
+{"frame":2,"detections":[{"bbox":[104,101],"score":0.9},{"bbox":[398,303,5],"score":0.8}]}
+{"frame":3,"detections":[{"bbox":[108,102],"score":0.9},{"bbox":[396,306,5],"score":0.8}]}
+{"frame":5,"detections":[{"bbox":[116,104],"score":0.9},{"bbox":[392,312,5],"score":0.8}]}
+`
+	for _, args := range [][]string{
+		{"--preset", "point"},
+		{"--motion-model", "constant-velocity-point", "--cost-function", "mahalanobis", "--max-point-distance", "4"},
+	} {
+		args = append(args, "--input-format", "jsonl", "--output-format", "csv", "--min-updates-use-prediction", "1", "--interpolate", "2")
+		out := bytes.Buffer{}
+		errOut := bytes.Buffer{}
+		err := run(args, strings.NewReader(in), &out, &errOut)
+		if err != nil {
+			t.Fatalf("Error running sort. args=%v err=%s", args, err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 11 || lines[0] != "frame,id,x,y,r,score" {
+			t.Fatalf("Unexpected output. args=%v output=%q", args, out.String())
+		}
+		ids := map[string]bool{}
+		for _, l := range lines[1:] {
+			ids[strings.Split(l, ",")[1]] = true
+		}
+		if len(ids) != 2 || !strings.Contains(out.String(), "\n4,") {
+			t.Errorf("Points should keep their IDs through the gap. args=%v output=%q", args, out.String())
+		}
+	}
+
+	err := run([]string{"--input-format", "jsonl", "--motion-model", "constant-velocity-point"}, strings.NewReader(in), &bytes.Buffer{}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "euclidean") {
+		t.Errorf("Points need a distance cost function. err=%v", err)
+	}
+}
+
+func TestRunNegativeScores(t *testing.T) {
+	//MOT17 DPM detections have negative scores
+	in := "1,-1,10,10,20,40,-0.5,-1,-1,-1\n2,-1,12,10,20,40,-0.3,-1,-1,-1\n3,-1,14,10,20,40,-0.4,-1,-1,-1\n"
+	out := bytes.Buffer{}
+	errOut := bytes.Buffer{}
+	err := run([]string{"--min-updates-use-prediction", "1"}, strings.NewReader(in), &out, &errOut)
+	if err != nil {
+		t.Fatalf("Error running sort. err=%s", err)
+	}
+	if strings.Count(out.String(), "\n") != 3 {
+		t.Errorf("Detections with negative scores should be tracked by default. output=%q", out.String())
+	}
+
+	out.Reset()
+	err = run([]string{"--min-updates-use-prediction", "1", "--min-score", "0"}, strings.NewReader(in), &out, &errOut)
+	if err != nil {
+		t.Fatalf("Error running sort. err=%s", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Detections below min-score should be ignored. output=%q", out.String())
+	}
+}
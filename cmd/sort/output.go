@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/mot"
+	"github.com/flaviostutz/sort/stream"
+)
+
+//trackWriter writes the tracks of each frame
+type trackWriter interface {
+	WriteTracks(frame int, tracks []sort.Track) error
+	Flush() error
+}
+
+//newTrackWriter creates a writer of tracks with boxSize values, the size of the boxes of the session motion model
+func newTrackWriter(format string, w io.Writer, boxSize int) (trackWriter, error) {
+	switch format {
+	case "mot":
+		if boxSize != 4 {
+			return nil, fmt.Errorf("output format mot only has 4 value boxes. use jsonl or csv for boxes with %d values", boxSize)
+		}
+		return mot.NewWriter(w), nil
+	case "csv":
+		return &csvWriter{w: bufio.NewWriter(w), boxSize: boxSize}, nil
+	case "jsonl":
+		return &jsonWriter{w: bufio.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+//boxColumns names the values of boxes with n values, as tracked by the motion models of each size
+func boxColumns(n int) string {
+	switch n {
+	case 3:
+		return "x,y,r"
+	case 5:
+		return "cx,cy,w,h,angle"
+	case 7:
+		return "x,y,z,l,w,h,yaw"
+	}
+	return "x1,y1,x2,y2"
+}
+
+//csvWriter writes lines in the form frame,id,x1,y1,x2,y2,score, or with the values of other boxes in place
+//of x1,y1,x2,y2. See boxColumns
+type csvWriter struct {
+	w       *bufio.Writer
+	boxSize int
+	header  bool
+}
+
+func (c *csvWriter) WriteTracks(frame int, tracks []sort.Track) error {
+	if !c.header {
+		c.header = true
+		_, err := fmt.Fprintf(c.w, "frame,id,%s,score\n", boxColumns(c.boxSize))
+		if err != nil {
+			return err
+		}
+	}
+	for _, t := range tracks {
+		_, err := fmt.Fprintf(c.w, "%d,%d,", frame, t.ID)
+		if err != nil {
+			return err
+		}
+		for _, v := range t.BBox {
+			_, err = fmt.Fprintf(c.w, "%.2f,", v)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(c.w, "%.2f\n", t.Score)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Flush() error {
+	return c.w.Flush()
+}
+
+//jsonWriter writes one stream.TrackedFrame per line
+type jsonWriter struct {
+	w *bufio.Writer
+}
+
+func (j *jsonWriter) WriteTracks(frame int, tracks []sort.Track) error {
+	b, err := json.Marshal(stream.TrackedFrame{Frame: frame, Tracks: tracks})
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(append(b, '\n'))
+	return err
+}
+
+func (j *jsonWriter) Flush() error {
+	return j.w.Flush()
+}
@@ -0,0 +1,61 @@
+package sort
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+//detectionScore is the column after the n box values of a detection (the 5th for [x1,y1,x2,y2]) or 1 when it has no score
+func detectionScore(bbox []float64, n int) float64 {
+	if len(bbox) > n {
+		return bbox[n]
+	}
+	return 1
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+//initConfidence sets the confidence of a tracker created from a detection with score
+func (k *KalmanBoxTracker) initConfidence(score float64) {
+	k.Confidence = clamp01(score) / 2
+}
+
+//raiseConfidence moves the confidence towards 1 by the detection score times the match score
+func (k *KalmanBoxTracker) raiseConfidence(score float64, match float64) {
+	k.Confidence = k.Confidence + (1-k.Confidence)*clamp01(score)*clamp01(match)
+}
+
+//decayConfidence lowers the confidence of a tracker that wasn't matched in this frame by decay and
+//by the growth of the standard deviation of its position since the previous frame
+func (k *KalmanBoxTracker) decayConfidence(decay float64) {
+	m := k.PredictsSinceUpdate
+	if m < 1 {
+		m = 1
+	}
+	growth := 1.0
+	v0 := k.positionVariance(m - 1)
+	v1 := k.positionVariance(m)
+	if v1 > 0 {
+		growth = math.Sqrt(v0 / v1)
+	}
+	k.Confidence = k.Confidence * (1 - decay) * math.Min(growth, 1)
+}
+
+//positionVariance is the variance of the box center steps frames after the one step prediction of the last update
+func (k *KalmanBoxTracker) positionVariance(steps int) float64 {
+	sys, nse, _ := k.MotionModel.System()
+	p := mat.DenseCopyOf(k.KalmanCtx.P)
+	for i := 0; i < steps; i++ {
+		var pn mat.Dense
+		pn.Product(sys.Ad, p, sys.Ad.T())
+		pn.Add(&pn, nse.Q)
+		p = &pn
+	}
+	var cp, cpc mat.Dense
+	cp.Mul(sys.C, p)
+	cpc.Mul(&cp, sys.C.T())
+	return cpc.At(0, 0) + cpc.At(1, 1)
+}
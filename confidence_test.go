@@ -0,0 +1,89 @@
+package sort
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConfidence(t *testing.T) {
+	s, err := NewSORT(WithMinUpdatesUsePrediction(1), WithMaxPredictsWithoutUpdate(100), WithMinConfidence(0.3), WithReportConfidence(0.6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := 0.0
+	reportedAt := 0
+	for f := 1; f <= 10; f++ {
+		x := 100 + 2*float64(f)
+		err = s.Update([][]float64{{x, 100, x + 40, 200, 0.9}, {500, 500, 540, 600, 0.1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := s.Trackers[0].Confidence
+		if c <= prev || c > 1 {
+			t.Fatalf("confidence should rise with matches. frame=%d confidence=%f previous=%f", f, c, prev)
+		}
+		prev = c
+		tracks := s.Tracks()
+		if reportedAt == 0 && len(tracks) > 0 {
+			reportedAt = f
+		}
+		for _, tr := range tracks {
+			if tr.Confidence < 0.6 {
+				t.Errorf("only confident tracks should be reported. track=%+v", tr)
+			}
+		}
+		if len(s.Trackers) == 2 && s.Trackers[1].Confidence >= c {
+			t.Errorf("low score detections should give lower confidence")
+		}
+	}
+	if len(s.Trackers) != 2 {
+		t.Errorf("low score detections should still be tracked. trackers=%d", len(s.Trackers))
+	}
+	if reportedAt < 2 || prev < 0.9 {
+		t.Errorf("track should become confident after some frames. reportedAt=%d confidence=%f", reportedAt, prev)
+	}
+
+	//coasting
+	id := s.Trackers[0].ID
+	frames := 0
+	for len(s.Trackers) > 0 && s.Trackers[0].ID == id {
+		//the low score tracker is removed by the confidence before the other one
+		err = s.Update([][]float64{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames++
+		if len(s.Trackers) > 0 && s.Trackers[0].Confidence >= prev {
+			t.Fatalf("confidence should decay while coasting")
+		}
+		if len(s.Trackers) > 0 {
+			prev = s.Trackers[0].Confidence
+		}
+	}
+	if frames < 3 || frames > 20 {
+		t.Errorf("tracker should be removed by MinConfidence. frames=%d", frames)
+	}
+
+	_, err = NewSORT(WithConfidenceDecay(1))
+	if err == nil {
+		t.Errorf("invalid decay should be rejected")
+	}
+}
+
+func TestTrackJSONZeroValues(t *testing.T) {
+	//a zero confidence or a heading along the x axis are valid values and must not be dropped
+	b, err := json.Marshal(Track{ID: 1, BBox: []float64{0, 0, 10, 10}, Velocity: []float64{2, 0}, Speed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]interface{}{}
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"confidence", "heading"} {
+		if _, ok := m[k]; !ok {
+			t.Errorf("%s should always be written. json=%s", k, b)
+		}
+	}
+}
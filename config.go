@@ -0,0 +1,234 @@
+package sort
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+//Config holds the tuning parameters of a SORT session. It can be loaded from YAML or JSON
+type Config struct {
+	//Preset names the preset used as base for the other values when loading a file
+	Preset string `json:"preset,omitempty" yaml:"preset,omitempty"`
+	//MaxPredictsWithoutUpdate is the number of frames a tracker survives without being matched to a detection
+	MaxPredictsWithoutUpdate int `json:"maxPredictsWithoutUpdate" yaml:"maxPredictsWithoutUpdate"`
+	//MinUpdatesUsePrediction is the number of updates before the Kalman prediction is used for association
+	MinUpdatesUsePrediction int `json:"minUpdatesUsePrediction" yaml:"minUpdatesUsePrediction"`
+	//IOUThreshold is the minimum cost function score for a detection to be matched to a tracker
+	IOUThreshold float64 `json:"iouThreshold" yaml:"iouThreshold"`
+	//MotionModel is the name of the Kalman model used by trackers. See NewMotionModel.
+	//"ground-plane" tracks positions on the ground plane of the Calibration. See GroundPlane
+	MotionModel string `json:"motionModel" yaml:"motionModel"`
+	//ProcessNoise scales the process noise of the motion model
+	ProcessNoise float64 `json:"processNoise" yaml:"processNoise"`
+	//CostFunction is the name of the detection x tracker score. See NewCostFunction
+	CostFunction string `json:"costFunction" yaml:"costFunction"`
+	//ConfidenceDecay is the fraction of the track confidence lost for each frame without a matched detection,
+	//on top of the loss caused by the growth of the position uncertainty
+	ConfidenceDecay float64 `json:"confidenceDecay" yaml:"confidenceDecay"`
+	//MinConfidence removes coasting trackers whose confidence falls below it instead of using MaxPredictsWithoutUpdate. 0 disables it
+	MinConfidence float64 `json:"minConfidence,omitempty" yaml:"minConfidence,omitempty"`
+	//ReportConfidence reports matched trackers whose confidence is at least this value instead of using
+	//MinUpdatesUsePrediction. 0 disables it
+	ReportConfidence float64 `json:"reportConfidence,omitempty" yaml:"reportConfidence,omitempty"`
+	//VelocitySmoothing is the weight of the previous value in the exponential smoothing of track velocities. 0 disables it
+	VelocitySmoothing float64 `json:"velocitySmoothing,omitempty" yaml:"velocitySmoothing,omitempty"`
+	//KeypointSigmas are the per keypoint constants of the oks cost function. COCOSigmas are used for 17 keypoints when empty
+	KeypointSigmas []float64 `json:"keypointSigmas,omitempty" yaml:"keypointSigmas,omitempty"`
+	//KeypointSmoothing enables One Euro filtering of track keypoints
+	KeypointSmoothing *OneEuro `json:"keypointSmoothing,omitempty" yaml:"keypointSmoothing,omitempty"`
+	//FrameWidth and FrameHeight are the image size in pixels. When set, predictions are clipped to the frame,
+	//coasting trackers predicted outside of it are removed and tracks crossing its border are flagged as exiting
+	FrameWidth  float64 `json:"frameWidth,omitempty" yaml:"frameWidth,omitempty"`
+	FrameHeight float64 `json:"frameHeight,omitempty" yaml:"frameHeight,omitempty"`
+	//MaxDistance prevents matching detections and trackers whose bottom centers are farther apart on the ground plane.
+	//It needs a calibration. 0 disables it
+	MaxDistance float64 `json:"maxDistance,omitempty" yaml:"maxDistance,omitempty"`
+	//MaxPointDistance prevents matching detections and trackers farther apart with the euclidean (pixels) and
+	//mahalanobis (standard deviations) cost functions, which use it instead of IOUThreshold
+	MaxPointDistance float64 `json:"maxPointDistance,omitempty" yaml:"maxPointDistance,omitempty"`
+	//ScoreNoise names the mapping from detection scores to measurement noise factors. See NewScoreNoise. Empty disables it
+	ScoreNoise string `json:"scoreNoise,omitempty" yaml:"scoreNoise,omitempty"`
+	//Calibration enables ground plane positions and speeds in meters per second
+	Calibration *Calibration `json:"calibration,omitempty" yaml:"calibration,omitempty"`
+}
+
+var presets = map[string]Config{
+	"pedestrian": {
+		Preset:                   "pedestrian",
+		MaxPredictsWithoutUpdate: 5,
+		MinUpdatesUsePrediction:  3,
+		IOUThreshold:             0.3,
+		MotionModel:              "constant-velocity",
+		ProcessNoise:             1,
+		CostFunction:             "iou",
+		ConfidenceDecay:          0.1,
+	},
+	"vehicle": {
+		Preset:                   "vehicle",
+		MaxPredictsWithoutUpdate: 10,
+		MinUpdatesUsePrediction:  2,
+		IOUThreshold:             0.2,
+		MotionModel:              "constant-velocity",
+		ProcessNoise:             2,
+		CostFunction:             "iou",
+		ConfidenceDecay:          0.1,
+	},
+	"drone": {
+		Preset:                   "drone",
+		MaxPredictsWithoutUpdate: 15,
+		MinUpdatesUsePrediction:  2,
+		IOUThreshold:             0.05,
+		MotionModel:              "constant-velocity",
+		ProcessNoise:             4,
+		CostFunction:             "giou",
+		ConfidenceDecay:          0.1,
+	},
+	"point": {
+		Preset:                   "point",
+		MaxPredictsWithoutUpdate: 5,
+		MinUpdatesUsePrediction:  3,
+		IOUThreshold:             0.3,
+		MotionModel:              "constant-velocity-point",
+		ProcessNoise:             1,
+		CostFunction:             "euclidean",
+		ConfidenceDecay:          0.1,
+		MaxPointDistance:         50,
+	},
+}
+
+//DefaultConfig returns the same parameters used by the original sort.py
+func DefaultConfig() Config {
+	return Config{
+		MaxPredictsWithoutUpdate: 1,
+		MinUpdatesUsePrediction:  3,
+		IOUThreshold:             0.3,
+		MotionModel:              "constant-velocity",
+		ProcessNoise:             1,
+		CostFunction:             "iou",
+		ConfidenceDecay:          0.1,
+	}
+}
+
+//Preset returns the configuration bundled with a named preset (pedestrian, vehicle, drone or point)
+func Preset(name string) (Config, error) {
+	c, ok := presets[name]
+	if !ok {
+		return Config{}, fmt.Errorf("unknown preset %q", name)
+	}
+	return c, nil
+}
+
+//Validate checks if all parameters are within their valid ranges
+func (c Config) Validate() error {
+	if c.MaxPredictsWithoutUpdate < 0 {
+		return fmt.Errorf("maxPredictsWithoutUpdate must be >= 0")
+	}
+	if c.MinUpdatesUsePrediction < 0 {
+		return fmt.Errorf("minUpdatesUsePrediction must be >= 0")
+	}
+	if c.IOUThreshold < 0 || c.IOUThreshold > 1 {
+		return fmt.Errorf("iouThreshold must be between 0 and 1")
+	}
+	if c.ProcessNoise <= 0 {
+		return fmt.Errorf("processNoise must be > 0")
+	}
+	if c.ConfidenceDecay < 0 || c.ConfidenceDecay >= 1 {
+		return fmt.Errorf("confidenceDecay must be >= 0 and < 1")
+	}
+	if c.MinConfidence < 0 || c.MinConfidence > 1 || c.ReportConfidence < 0 || c.ReportConfidence > 1 {
+		return fmt.Errorf("minConfidence and reportConfidence must be between 0 and 1")
+	}
+	if c.VelocitySmoothing < 0 || c.VelocitySmoothing >= 1 {
+		return fmt.Errorf("velocitySmoothing must be >= 0 and < 1")
+	}
+	for _, s := range c.KeypointSigmas {
+		if s <= 0 {
+			return fmt.Errorf("keypointSigmas must be > 0")
+		}
+	}
+	if c.KeypointSmoothing != nil {
+		err := c.KeypointSmoothing.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.FrameWidth < 0 || c.FrameHeight < 0 {
+		return fmt.Errorf("frameWidth and frameHeight must be >= 0")
+	}
+	if c.Calibration != nil {
+		err := c.Calibration.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if c.MaxDistance < 0 {
+		return fmt.Errorf("maxDistance must be >= 0")
+	}
+	if c.MaxDistance > 0 && c.Calibration == nil {
+		return fmt.Errorf("maxDistance needs a calibration")
+	}
+	if c.MaxPointDistance < 0 {
+		return fmt.Errorf("maxPointDistance must be >= 0")
+	}
+	if distanceCost(c.CostFunction) && c.MaxPointDistance == 0 {
+		return fmt.Errorf("cost function %s needs maxPointDistance", c.CostFunction)
+	}
+	if c.ScoreNoise != "" {
+		_, err := NewScoreNoise(c.ScoreNoise)
+		if err != nil {
+			return err
+		}
+	}
+	m, err := newMotionModel(c)
+	if err != nil {
+		return err
+	}
+	_, err = NewCostFunction(c.CostFunction)
+	if err != nil {
+		return err
+	}
+	_, points := m.(ConstantVelocityPoint)
+	if points && !distanceCost(c.CostFunction) {
+		return fmt.Errorf("motion model %s needs the euclidean or mahalanobis cost function. See the point preset", c.MotionModel)
+	}
+	n := costBoxSize(c.CostFunction)
+	if n > 0 && n != boxSize(m) {
+		return fmt.Errorf("cost function %s compares boxes with %d values but motion model %s tracks boxes with %d", c.CostFunction, n, c.MotionModel, boxSize(m))
+	}
+	return nil
+}
+
+//ParseConfig decodes a Config from YAML or JSON data. Values not present in data are taken
+//from the preset named in it or from DefaultConfig
+func ParseConfig(data []byte) (Config, error) {
+	base := struct {
+		Preset string `yaml:"preset"`
+	}{}
+	err := yaml.Unmarshal(data, &base)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config. err=%s", err)
+	}
+	c := DefaultConfig()
+	if base.Preset != "" {
+		c, err = Preset(base.Preset)
+		if err != nil {
+			return Config{}, err
+		}
+	}
+	err = yaml.Unmarshal(data, &c)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config. err=%s", err)
+	}
+	return c, c.Validate()
+}
+
+//LoadConfig reads a Config from a YAML or JSON file
+func LoadConfig(file string) (Config, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return Config{}, err
+	}
+	return ParseConfig(data)
+}
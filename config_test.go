@@ -0,0 +1,60 @@
+package sort
+
+import (
+	"testing"
+)
+
+func TestNewSORTOptions(t *testing.T) {
+	s, err := NewSORT(WithPreset("vehicle"), WithIOUThreshold(0.4))
+	if err != nil {
+		t.Fatalf("Error creating SORT. err=%s", err)
+	}
+	c := s.Config()
+	if c.IOUThreshold != 0.4 || c.MaxPredictsWithoutUpdate != 10 {
+		t.Errorf("Options not applied. config=%+v", c)
+	}
+
+	_, err = NewSORT(WithIOUThreshold(-0.1))
+	if err == nil {
+		t.Errorf("Negative IOU threshold should be rejected")
+	}
+	_, err = NewSORT(WithMotionModel("teleport"))
+	if err == nil {
+		t.Errorf("Unknown motion model should be rejected")
+	}
+	_, err = NewSORT(WithPreset("submarine"))
+	if err == nil {
+		t.Errorf("Unknown preset should be rejected")
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig([]byte("preset: drone\niouThreshold: 0.1\n"))
+	if err != nil {
+		t.Fatalf("Error parsing yaml config. err=%s", err)
+	}
+	if c.IOUThreshold != 0.1 || c.CostFunction != "giou" || c.MaxPredictsWithoutUpdate != 15 {
+		t.Errorf("Unexpected yaml config %+v", c)
+	}
+
+	c, err = ParseConfig([]byte(`{"maxPredictsWithoutUpdate": 7}`))
+	if err != nil {
+		t.Fatalf("Error parsing json config. err=%s", err)
+	}
+	if c.MaxPredictsWithoutUpdate != 7 || c.IOUThreshold != DefaultConfig().IOUThreshold {
+		t.Errorf("Unexpected json config %+v", c)
+	}
+
+	c, err = ParseConfig([]byte("calibration:\n  homography: [0.05,0,0,0,0.05,0,0,0,1]\n  fps: 25\n"))
+	if err != nil {
+		t.Fatalf("Error parsing calibration. err=%s", err)
+	}
+	if c.Calibration == nil || c.Calibration.FPS != 25 || c.Calibration.Homography[4] != 0.05 {
+		t.Errorf("Unexpected calibration %+v", c.Calibration)
+	}
+
+	_, err = ParseConfig([]byte(`{"processNoise": 0}`))
+	if err == nil {
+		t.Errorf("Zero process noise should be rejected")
+	}
+}
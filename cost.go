@@ -0,0 +1,48 @@
+package sort
+
+import "fmt"
+
+//CostFunction scores how well a detection matches the reference bounding box of a tracker.
+//Higher values mean better matches. Association minimizes 1-score and drops pairs scoring below the session threshold
+type CostFunction func(det []float64, trk []float64) float64
+
+//NewCostFunction returns the cost function registered with name
+func NewCostFunction(name string) (CostFunction, error) {
+	switch name {
+	case "iou":
+		return IOU, nil
+	case "giou":
+		return GIOU, nil
+	case "iou-3d":
+		return IOU3D, nil
+	case "bev-iou":
+		return BEVIOU, nil
+	case "rotated-iou":
+		return RotatedIOU, nil
+	case "oks":
+		//keypoints are compared during association. Boxes are used when they are missing
+		return IOU, nil
+	case "euclidean", "mahalanobis":
+		//association gates distances with MaxPointDistance. This score is only meant for points
+		return func(det []float64, trk []float64) float64 {
+			return 1 / (1 + CenterDistance(det, trk))
+		}, nil
+	case "mask-iou":
+		//masks are compared during association. Boxes are used when they are missing
+		return IOU, nil
+	}
+	return nil, fmt.Errorf("unknown cost function %q", name)
+}
+
+//costBoxSize is the number of values of the boxes compared by a cost function, or 0 when it works with any box
+func costBoxSize(name string) int {
+	switch name {
+	case "iou", "giou", "oks", "mask-iou":
+		return 4
+	case "rotated-iou":
+		return 5
+	case "iou-3d", "bev-iou":
+		return 7
+	}
+	return 0
+}
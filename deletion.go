@@ -0,0 +1,146 @@
+package sort
+
+import (
+	"fmt"
+	"math"
+)
+
+//DeletionPolicy decides which trackers are removed from a session at the end of each Update
+type DeletionPolicy interface {
+	//Delete tells whether trk must be removed
+	Delete(trk *KalmanBoxTracker, state SessionState) bool
+}
+
+//SessionState is the session information available to deletion policies
+type SessionState struct {
+	Config     Config
+	FrameCount int
+	//Updated tells whether the tracker was matched to a detection in this frame
+	Updated bool
+	//Trackers is the number of trackers alive in the session, a measure of the scene density
+	Trackers int
+}
+
+//Confirmed tells whether trk had enough updates to be reported
+func (s SessionState) Confirmed(trk *KalmanBoxTracker) bool {
+	return trk.Updates >= s.Config.MinUpdatesUsePrediction
+}
+
+//Outside tells whether trk is coasting and predicted entirely outside the session frame, if known
+func (s SessionState) Outside(trk *KalmanBoxTracker) bool {
+	if s.Updated || !s.Config.HasFrame() {
+		return false
+	}
+	b := predictedBox(trk)
+	return b != nil && OutsideFrame(b, s.Config.FrameWidth, s.Config.FrameHeight)
+}
+
+//DefaultDeletionPolicy removes trackers after Config.MaxPredictsWithoutUpdate predictions without a detection,
+//or when Config.MinConfidence is set and their confidence falls below it. It is the original sort.py behavior,
+//except that coasting trackers predicted outside the frame are removed at once when the frame size is known
+type DefaultDeletionPolicy struct{}
+
+//Delete tells whether trk must be removed
+func (p DefaultDeletionPolicy) Delete(trk *KalmanBoxTracker, state SessionState) bool {
+	c := state.Config
+	expired := trk.PredictsSinceUpdate > c.MaxPredictsWithoutUpdate
+	if c.MinConfidence > 0 {
+		expired = !state.Updated && trk.Confidence < c.MinConfidence
+	}
+	return expired || trk.SkipPredicts > c.MinUpdatesUsePrediction+1 || state.Outside(trk)
+}
+
+//AdaptiveDeletionPolicy gives long lived trackers more frames to coast than one frame ghosts,
+//shrinks budgets in crowded scenes and removes trackers leaving or outside the frame right away
+type AdaptiveDeletionPolicy struct {
+	//TentativeMaxMisses is the number of frames without detections a tracker survives before it is confirmed
+	TentativeMaxMisses int
+	//ConfirmedMaxMisses is the base number of frames without detections a confirmed tracker survives
+	ConfirmedMaxMisses int
+	//AgeFactor adds frames to the budget of confirmed trackers for each update they had
+	AgeFactor float64
+	//MaxMisses caps the budget of confirmed trackers
+	MaxMisses int
+	//DensityFactor divides budgets by 1+DensityFactor*(trackers-1). 0 disables it
+	DensityFactor float64
+	//FrameWidth and FrameHeight enable border aware deletion. When not set, the session frame size is used
+	FrameWidth  float64
+	FrameHeight float64
+	//Border is the distance in pixels to the frame edge where coasting trackers moving outwards are removed
+	Border float64
+}
+
+//NewAdaptiveDeletionPolicy returns a policy with budgets that work for pedestrians at 25-30 fps
+func NewAdaptiveDeletionPolicy() AdaptiveDeletionPolicy {
+	return AdaptiveDeletionPolicy{
+		TentativeMaxMisses: 1,
+		ConfirmedMaxMisses: 3,
+		AgeFactor:          0.5,
+		MaxMisses:          30,
+		DensityFactor:      0,
+		Border:             5,
+	}
+}
+
+//Validate checks that parameters are in their valid ranges
+func (p AdaptiveDeletionPolicy) Validate() error {
+	if p.TentativeMaxMisses < 0 || p.ConfirmedMaxMisses < 0 || p.MaxMisses < 0 {
+		return fmt.Errorf("max misses must be >= 0")
+	}
+	if p.AgeFactor < 0 || p.DensityFactor < 0 || p.Border < 0 {
+		return fmt.Errorf("ageFactor, densityFactor and border must be >= 0")
+	}
+	if p.FrameWidth < 0 || p.FrameHeight < 0 {
+		return fmt.Errorf("frame size must be >= 0")
+	}
+	return nil
+}
+
+//Budget returns the number of frames trk may go without detections
+func (p AdaptiveDeletionPolicy) Budget(trk *KalmanBoxTracker, state SessionState) float64 {
+	budget := float64(p.TentativeMaxMisses)
+	if state.Confirmed(trk) {
+		budget = math.Min(float64(p.ConfirmedMaxMisses)+p.AgeFactor*float64(trk.Updates), float64(p.MaxMisses))
+	}
+	if p.DensityFactor > 0 && state.Trackers > 1 {
+		budget = budget / (1 + p.DensityFactor*float64(state.Trackers-1))
+	}
+	return budget
+}
+
+//Delete tells whether trk must be removed
+func (p AdaptiveDeletionPolicy) Delete(trk *KalmanBoxTracker, state SessionState) bool {
+	if state.Updated {
+		return false
+	}
+	if float64(trk.FramesSinceUpdate) > p.Budget(trk, state) || state.Outside(trk) {
+		return true
+	}
+	w, h := p.FrameWidth, p.FrameHeight
+	if w == 0 || h == 0 {
+		w, h = state.Config.FrameWidth, state.Config.FrameHeight
+	}
+	return w > 0 && h > 0 && p.leaving(trk, w, h)
+}
+
+//leavingSpeed is the speed in pixels per frame above which a box at the border is moving outwards. Slower boxes
+//are stationary objects, which coast there as anywhere else
+const leavingSpeed = 0.1
+
+//leaving tells whether the predicted box is at the border of a width x height frame and moving outwards.
+//Motion models without velocities are never leaving
+func (p AdaptiveDeletionPolicy) leaving(trk *KalmanBoxTracker, width, height float64) bool {
+	b := predictedBox(trk)
+	if b == nil {
+		return false
+	}
+	vm, ok := trk.MotionModel.(VelocityModel)
+	if !ok {
+		return false
+	}
+	v := vm.Velocity(trk.KalmanCtx.X)
+	return (b[0] <= p.Border && v[0] < -leavingSpeed) ||
+		(b[1] <= p.Border && v[1] < -leavingSpeed) ||
+		(b[2] >= width-p.Border && v[0] > leavingSpeed) ||
+		(b[3] >= height-p.Border && v[1] > leavingSpeed)
+}
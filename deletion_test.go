@@ -0,0 +1,166 @@
+package sort
+
+import (
+	"testing"
+)
+
+//coastFrames tracks a box for n frames and returns how many frames without detections it survives
+func coastFrames(t *testing.T, p DeletionPolicy, n int) int {
+	s, err := NewSORT(WithMinUpdatesUsePrediction(3), WithDeletionPolicy(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := 0; f < n; f++ {
+		x := 200 + 2*float64(f)
+		err = s.Update([][]float64{{x, 100, x + 40, 200, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	frames := 0
+	for len(s.Trackers) > 0 {
+		err = s.Update([][]float64{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames++
+		if frames > 100 {
+			t.Fatalf("tracker never removed")
+		}
+	}
+	return frames - 1
+}
+
+func TestAdaptiveDeletion(t *testing.T) {
+	p := NewAdaptiveDeletionPolicy()
+	tentative := coastFrames(t, p, 1)
+	short := coastFrames(t, p, 5)
+	long := coastFrames(t, p, 80)
+	if tentative != p.TentativeMaxMisses {
+		t.Errorf("tentative tracker should survive %d frames. frames=%d", p.TentativeMaxMisses, tentative)
+	}
+	if short <= tentative || long <= short {
+		t.Errorf("older trackers should coast longer. tentative=%d short=%d long=%d", tentative, short, long)
+	}
+	if long != p.MaxMisses {
+		t.Errorf("coasting should be capped. frames=%d", long)
+	}
+
+	p.DensityFactor = 0.5
+	st := SessionState{Config: DefaultConfig(), Trackers: 5}
+	trk := &KalmanBoxTracker{Updates: 10}
+	st.Config.MinUpdatesUsePrediction = 3
+	if b := p.Budget(trk, st); b != 8.0/3 {
+		t.Errorf("budget should shrink in crowded scenes. budget=%f", b)
+	}
+
+	if NewAdaptiveDeletionPolicy().Validate() != nil {
+		t.Errorf("default policy should be valid")
+	}
+	_, err := NewSORT(WithDeletionPolicy(AdaptiveDeletionPolicy{AgeFactor: -1}))
+	if err == nil {
+		t.Errorf("invalid policy should be rejected")
+	}
+}
+
+func TestBorderDeletion(t *testing.T) {
+	p := NewAdaptiveDeletionPolicy()
+	p.FrameWidth = 640
+	p.FrameHeight = 480
+	s, err := NewSORT(WithMinUpdatesUsePrediction(3), WithDeletionPolicy(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//leaving on the left, and still inside the frame
+	for f := 0; f < 20; f++ {
+		x := 100 - 5*float64(f)
+		err = s.Update([][]float64{{x, 100, x + 40, 200, 0.9}, {300, 100, 340, 200, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.Trackers) != 2 {
+		t.Fatalf("both objects should be tracked. trackers=%d", len(s.Trackers))
+	}
+	err = s.Update([][]float64{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Trackers) != 1 || s.Trackers[0].LastBBox[0] != 300 {
+		t.Errorf("only the tracker leaving the frame should be removed. trackers=%d", len(s.Trackers))
+	}
+
+	//a parked car at the border is not leaving
+	s, err = NewSORT(WithMinUpdatesUsePrediction(3), WithDeletionPolicy(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := 0; f < 20; f++ {
+		err = s.Update([][]float64{{0, 100, 40, 200, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = s.Update([][]float64{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Trackers) != 1 {
+		t.Errorf("stationary tracker at the border should survive a miss")
+	}
+}
+
+type strictDeletion struct{}
+
+func (p strictDeletion) Delete(trk *KalmanBoxTracker, state SessionState) bool {
+	return !state.Updated
+}
+
+func TestCustomDeletion(t *testing.T) {
+	if coastFrames(t, DefaultDeletionPolicy{}, 10) != DefaultConfig().MaxPredictsWithoutUpdate {
+		t.Errorf("default policy should follow MaxPredictsWithoutUpdate")
+	}
+	if coastFrames(t, strictDeletion{}, 10) != 0 {
+		t.Errorf("custom policy not used")
+	}
+	_, err := NewSORT(WithDeletionPolicy(nil))
+	if err == nil {
+		t.Errorf("nil policy should be rejected")
+	}
+}
+
+type keepDeletion struct{}
+
+func (p keepDeletion) Delete(trk *KalmanBoxTracker, state SessionState) bool {
+	return false
+}
+
+func TestOutsideFrameDeletion(t *testing.T) {
+	//the tracker moves out of a 640x480 frame to the left
+	coast := func(p DeletionPolicy) int {
+		s, err := NewSORT(WithMinUpdatesUsePrediction(3), WithMaxPredictsWithoutUpdate(50), WithFrameSize(640, 480), WithDeletionPolicy(p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for f := 0; f < 10; f++ {
+			x := 100 - 10*float64(f)
+			err = s.Update([][]float64{{x, 100, x + 40, 200, 0.9}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		for f := 0; f < 20; f++ {
+			err = s.Update([][]float64{})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return len(s.Trackers)
+	}
+	if coast(DefaultDeletionPolicy{}) != 0 {
+		t.Errorf("default policy should remove trackers outside the frame")
+	}
+	if coast(keepDeletion{}) != 1 {
+		t.Errorf("custom policy should decide about trackers outside the frame")
+	}
+}
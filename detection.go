@@ -0,0 +1,48 @@
+package sort
+
+import (
+	"encoding/json"
+)
+
+//Detection is an object found by a detector in a frame
+type Detection struct {
+	//BBox is in the form [x1,y1,x2,y2], [cx,cy,w,h,angle] with oriented boxes or [x,y,z,l,w,h,yaw] with 3D motion models
+	BBox []float64 `json:"bbox"`
+	//Score is the detector confidence. It is 1 when omitted in JSON. Scores <= 0 do not scale the measurement noise
+	Score float64 `json:"score"`
+	//Class is the optional object class reported by the detector
+	Class string `json:"class,omitempty"`
+	//Embedding is an optional appearance feature vector
+	Embedding []float64 `json:"embedding,omitempty"`
+	//Keypoints are the optional joints of a pose detector, e.g. the 17 COCO body keypoints
+	Keypoints []Keypoint `json:"keypoints,omitempty"`
+	//Mask is the optional instance segmentation mask in COCO RLE format
+	Mask *RLE `json:"mask,omitempty"`
+}
+
+//UnmarshalJSON decodes a detection defaulting Score to 1
+func (d *Detection) UnmarshalJSON(data []byte) error {
+	type detection Detection
+	v := detection{Score: 1}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*d = Detection(v)
+	return nil
+}
+
+//keeps detection attributes that are not part of the Kalman state
+func setAttributes(trk *KalmanBoxTracker, dets []Detection, i int, smoothing *OneEuro) {
+	if dets == nil {
+		return
+	}
+	trk.Class = dets[i].Class
+	if dets[i].Embedding != nil {
+		trk.Embedding = copyOf(dets[i].Embedding)
+	}
+	trk.updateKeypoints(dets[i].Keypoints, smoothing)
+	if dets[i].Mask != nil {
+		trk.Mask = copyMask(dets[i].Mask)
+	}
+}
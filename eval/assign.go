@@ -0,0 +1,44 @@
+package eval
+
+import (
+	"github.com/cpmech/gosl/graph"
+)
+
+//minimize solves the linear assignment problem for a (possibly rectangular) cost matrix
+//and returns the [row,col] pairs, as scipy's linear_sum_assignment does
+func minimize(cost [][]float64) [][2]int {
+	if len(cost) == 0 || len(cost[0]) == 0 {
+		return [][2]int{}
+	}
+	mk := graph.Munkres{}
+	mk.Init(len(cost), len(cost[0]))
+	mk.SetCostMatrix(cost)
+	mk.Run()
+	pairs := make([][2]int, 0)
+	for i, j := range mk.Links {
+		if j != -1 {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return pairs
+}
+
+//maximize solves the linear assignment problem maximizing the total score
+func maximize(score [][]float64) [][2]int {
+	max := 0.0
+	for _, r := range score {
+		for _, v := range r {
+			if v > max {
+				max = v
+			}
+		}
+	}
+	cost := make([][]float64, len(score))
+	for i, r := range score {
+		cost[i] = make([]float64, len(r))
+		for j, v := range r {
+			cost[i][j] = max - v
+		}
+	}
+	return minimize(cost)
+}
@@ -0,0 +1,149 @@
+package eval
+
+//CLEAR holds the CLEAR MOT metrics
+type CLEAR struct {
+	MOTA float64 `json:"mota"`
+	MOTP float64 `json:"motp"`
+	MODA float64 `json:"moda"`
+	//Recall and Precision of detections
+	Recall    float64 `json:"recall"`
+	Precision float64 `json:"precision"`
+	TP        int     `json:"tp"`
+	FP        int     `json:"fp"`
+	FN        int     `json:"fn"`
+	IDSW      int     `json:"idsw"`
+	Frag      int     `json:"frag"`
+	//MT (mostly tracked) has ground truth objects tracked for more than 80% of their lifespan,
+	//ML (mostly lost) for less than 20% and PT (partially tracked) the rest
+	MT int `json:"mt"`
+	PT int `json:"pt"`
+	ML int `json:"ml"`
+}
+
+const clearThreshold = 0.5
+
+func evalCLEAR(seq sequence) CLEAR {
+	r := CLEAR{}
+	if seq.numTrkDets == 0 {
+		r.FN = seq.numGtDets
+		r.ML = seq.numGtIDs
+		return r
+	}
+	if seq.numGtDets == 0 {
+		r.FP = seq.numTrkDets
+		return r
+	}
+
+	gtIDCount := make([]int, seq.numGtIDs)
+	gtMatchedCount := make([]int, seq.numGtIDs)
+	gtFragCount := make([]int, seq.numGtIDs)
+	//tracker ID last matched to each gt (for IDSW) and matched in the previous timestep (for matching and Frag). -1 for none
+	prevTrackerID := filled(seq.numGtIDs, -1)
+	prevTimestepTrackerID := filled(seq.numGtIDs, -1)
+	motpSum := 0.0
+
+	for t := 0; t < seq.numTimestep; t++ {
+		gtIDs := seq.gtIDs[t]
+		trkIDs := seq.trackerIDs[t]
+		if len(gtIDs) == 0 {
+			r.FP = r.FP + len(trkIDs)
+			continue
+		}
+		if len(trkIDs) == 0 {
+			r.FN = r.FN + len(gtIDs)
+			for _, g := range gtIDs {
+				gtIDCount[g]++
+			}
+			continue
+		}
+
+		//prefer keeping the previous association
+		sim := seq.similarity[t]
+		score := make([][]float64, len(gtIDs))
+		for i, g := range gtIDs {
+			score[i] = make([]float64, len(trkIDs))
+			for j, tr := range trkIDs {
+				if sim[i][j] < clearThreshold-eps {
+					continue
+				}
+				score[i][j] = sim[i][j]
+				if prevTimestepTrackerID[g] == tr {
+					score[i][j] = score[i][j] + 1000
+				}
+			}
+		}
+
+		matches := make([][2]int, 0)
+		for _, m := range maximize(score) {
+			if score[m[0]][m[1]] > eps {
+				matches = append(matches, m)
+			}
+		}
+
+		for _, g := range gtIDs {
+			gtIDCount[g]++
+		}
+		notPreviouslyTracked := make([]bool, seq.numGtIDs)
+		for g, p := range prevTimestepTrackerID {
+			notPreviouslyTracked[g] = p == -1
+			prevTimestepTrackerID[g] = -1
+		}
+		for _, m := range matches {
+			g := gtIDs[m[0]]
+			tr := trkIDs[m[1]]
+			if prevTrackerID[g] != -1 && prevTrackerID[g] != tr {
+				r.IDSW++
+			}
+			gtMatchedCount[g]++
+			prevTrackerID[g] = tr
+			prevTimestepTrackerID[g] = tr
+			if notPreviouslyTracked[g] {
+				gtFragCount[g]++
+			}
+			motpSum = motpSum + sim[m[0]][m[1]]
+		}
+
+		r.TP = r.TP + len(matches)
+		r.FN = r.FN + len(gtIDs) - len(matches)
+		r.FP = r.FP + len(trkIDs) - len(matches)
+	}
+
+	for g := 0; g < seq.numGtIDs; g++ {
+		if gtFragCount[g] > 0 {
+			r.Frag = r.Frag + gtFragCount[g] - 1
+		}
+		if gtIDCount[g] == 0 {
+			continue
+		}
+		ratio := float64(gtMatchedCount[g]) / float64(gtIDCount[g])
+		if ratio > 0.8 {
+			r.MT++
+		} else if ratio >= 0.2 {
+			r.PT++
+		}
+	}
+	r.ML = seq.numGtIDs - r.MT - r.PT
+
+	tp := float64(r.TP)
+	r.MOTP = motpSum / maxf(1, tp)
+	r.Recall = tp / maxf(1, tp+float64(r.FN))
+	r.Precision = tp / maxf(1, tp+float64(r.FP))
+	r.MODA = (tp - float64(r.FP)) / maxf(1, tp+float64(r.FN))
+	r.MOTA = (tp - float64(r.FP) - float64(r.IDSW)) / maxf(1, tp+float64(r.FN))
+	return r
+}
+
+func filled(n int, v int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = v
+	}
+	return s
+}
+
+func maxf(a float64, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,167 @@
+//Package eval computes CLEAR MOT, Identity (IDF1) and HOTA tracking metrics the same way TrackEval does
+//for the MOTChallenge benchmark
+package eval
+
+import (
+	"math"
+
+	"github.com/flaviostutz/sort/mot"
+)
+
+//Result holds all metrics of a sequence
+type Result struct {
+	CLEAR    CLEAR    `json:"clear"`
+	Identity Identity `json:"identity"`
+	HOTA     HOTA     `json:"hota"`
+}
+
+//Evaluate computes all metrics for tracker results against ground truth rows of a single sequence.
+//If ground truth rows have classes (gt.txt with 9 columns), the MOTChallenge preprocessing is applied: tracker boxes matching
+//distractor classes are removed and only non ignored pedestrians are evaluated
+func Evaluate(gt []mot.Row, tracker []mot.Row) Result {
+	seq := preprocess(gt, tracker)
+	return Result{
+		CLEAR:    evalCLEAR(seq),
+		Identity: evalIdentity(seq),
+		HOTA:     evalHOTA(seq),
+	}
+}
+
+//MOTChallenge classes that must not be counted as false positives when tracked
+var distractorClasses = map[int]bool{
+	2:  true, //person_on_vehicle
+	6:  true, //non_mot_vehicle
+	7:  true, //static_person
+	8:  true, //distractor
+	12: true, //reflection
+}
+
+const pedestrianClass = 1
+
+//sequence holds ground truth and tracker data with IDs relabeled to 0..n-1
+type sequence struct {
+	gtIDs       [][]int
+	trackerIDs  [][]int
+	similarity  [][][]float64
+	numGtIDs    int
+	numTrkIDs   int
+	numGtDets   int
+	numTrkDets  int
+	numTimestep int
+}
+
+func preprocess(gt []mot.Row, tracker []mot.Row) sequence {
+	gtFrames := mot.GroupByFrame(gt)
+	trkFrames := mot.GroupByFrame(tracker)
+	n := len(gtFrames)
+	if len(trkFrames) > n {
+		n = len(trkFrames)
+	}
+
+	seq := sequence{
+		gtIDs:       make([][]int, n),
+		trackerIDs:  make([][]int, n),
+		similarity:  make([][][]float64, n),
+		numTimestep: n,
+	}
+	gtLabels := make(map[int64]int)
+	trkLabels := make(map[int64]int)
+
+	for t := 0; t < n; t++ {
+		gts := []mot.Row{}
+		if t < len(gtFrames) {
+			gts = gtFrames[t]
+		}
+		trks := []mot.Row{}
+		if t < len(trkFrames) {
+			trks = trkFrames[t]
+		}
+
+		//remove tracker boxes matched to distractors
+		removeTrk := make(map[int]bool)
+		if len(gts) > 0 && len(trks) > 0 {
+			sim := similarities(gts, trks)
+			score := make([][]float64, len(gts))
+			for i := range sim {
+				score[i] = make([]float64, len(trks))
+				for j, v := range sim[i] {
+					if v >= 0.5-eps {
+						score[i][j] = v
+					}
+				}
+			}
+			for _, m := range maximize(score) {
+				if score[m[0]][m[1]] > eps && distractorClasses[gts[m[0]].Class] {
+					removeTrk[m[1]] = true
+				}
+			}
+		}
+
+		keptGt := make([]mot.Row, 0)
+		for _, g := range gts {
+			if g.Conf == 0 {
+				continue
+			}
+			if g.Class != -1 && g.Class != pedestrianClass {
+				continue
+			}
+			keptGt = append(keptGt, g)
+		}
+		keptTrk := make([]mot.Row, 0)
+		for j, tr := range trks {
+			if !removeTrk[j] {
+				keptTrk = append(keptTrk, tr)
+			}
+		}
+
+		seq.gtIDs[t] = make([]int, len(keptGt))
+		for i, g := range keptGt {
+			seq.gtIDs[t][i] = label(gtLabels, g.ID)
+		}
+		seq.trackerIDs[t] = make([]int, len(keptTrk))
+		for j, tr := range keptTrk {
+			seq.trackerIDs[t][j] = label(trkLabels, tr.ID)
+		}
+		seq.similarity[t] = similarities(keptGt, keptTrk)
+		seq.numGtDets = seq.numGtDets + len(keptGt)
+		seq.numTrkDets = seq.numTrkDets + len(keptTrk)
+	}
+	seq.numGtIDs = len(gtLabels)
+	seq.numTrkIDs = len(trkLabels)
+	return seq
+}
+
+func label(labels map[int64]int, id int64) int {
+	l, ok := labels[id]
+	if !ok {
+		l = len(labels)
+		labels[id] = l
+	}
+	return l
+}
+
+//eps mimics np.finfo('float').eps used in TrackEval comparisons
+const eps = 2.220446049250313e-16
+
+func similarities(gts []mot.Row, trks []mot.Row) [][]float64 {
+	sim := make([][]float64, len(gts))
+	for i, g := range gts {
+		sim[i] = make([]float64, len(trks))
+		for j, tr := range trks {
+			sim[i][j] = boxIOU(g.BBox, tr.BBox)
+		}
+	}
+	return sim
+}
+
+//boxIOU computes the IOU of two [x1,y1,x2,y2] boxes
+func boxIOU(a []float64, b []float64) float64 {
+	w := math.Max(0, math.Min(a[2], b[2])-math.Max(a[0], b[0]))
+	h := math.Max(0, math.Min(a[3], b[3])-math.Max(a[1], b[1]))
+	inter := w * h
+	union := (a[2]-a[0])*(a[3]-a[1]) + (b[2]-b[0])*(b[3]-b[1]) - inter
+	if union <= eps {
+		return 0
+	}
+	return inter / union
+}
@@ -0,0 +1,61 @@
+package eval
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flaviostutz/sort/mot"
+)
+
+//Expected values follow TrackEval (MotChallenge2DBox with preprocessing) for testdata fixture.
+//Tracker 15 follows a static person (distractor) and is removed, gt 5 is zero marked and is ignored
+func TestEvaluate(t *testing.T) {
+	gt, err := mot.ReadFile("testdata/gt.txt")
+	if err != nil {
+		t.Fatalf("Error reading gt. err=%s", err)
+	}
+	trk, err := mot.ReadFile("testdata/tracker.txt")
+	if err != nil {
+		t.Fatalf("Error reading tracker results. err=%s", err)
+	}
+	r := Evaluate(gt, trk)
+
+	c := r.CLEAR
+	if c.TP != 14 || c.FP != 3 || c.FN != 2 || c.IDSW != 1 || c.Frag != 1 || c.MT != 2 || c.PT != 1 || c.ML != 0 {
+		t.Errorf("Unexpected CLEAR counts %+v", c)
+	}
+	assertMetric(t, "MOTA", c.MOTA, 0.625)
+	assertMetric(t, "MOTP", c.MOTP, 0.80417966214479797)
+
+	i := r.Identity
+	if i.IDTP != 12 || i.IDFP != 5 || i.IDFN != 4 {
+		t.Errorf("Unexpected Identity counts %+v", i)
+	}
+	assertMetric(t, "IDF1", i.IDF1, 0.72727272727272729)
+	assertMetric(t, "IDP", i.IDP, 0.70588235294117652)
+	assertMetric(t, "IDR", i.IDR, 0.75)
+
+	h := r.HOTA
+	assertMetric(t, "HOTA", h.HOTA, 0.61865185406152534)
+	assertMetric(t, "DetA", h.DetA, 0.58772503226882322)
+	assertMetric(t, "AssA", h.AssA, 0.66087092731829544)
+	assertMetric(t, "LocA", h.LocA, 0.83400767181892133)
+}
+
+func TestEvaluateEmpty(t *testing.T) {
+	gt, err := mot.ReadFile("testdata/gt.txt")
+	if err != nil {
+		t.Fatalf("Error reading gt. err=%s", err)
+	}
+	r := Evaluate(gt, []mot.Row{})
+	if r.CLEAR.FN != 16 || r.CLEAR.ML != 3 || r.Identity.IDFN != 16 || r.HOTA.HOTA != 0 {
+		t.Errorf("Unexpected metrics for empty tracker %+v", r)
+	}
+	assertMetric(t, "LocA", r.HOTA.LocA, 1)
+}
+
+func assertMetric(t *testing.T, name string, value float64, expected float64) {
+	if math.Abs(value-expected) > 1e-9 {
+		t.Errorf("%s=%v, expected %v", name, value, expected)
+	}
+}
@@ -0,0 +1,173 @@
+package eval
+
+import (
+	"math"
+)
+
+//HOTA holds the Higher Order Tracking Accuracy metrics. Values are averaged over the localization thresholds 0.05 to 0.95
+type HOTA struct {
+	HOTA  float64 `json:"hota"`
+	DetA  float64 `json:"deta"`
+	AssA  float64 `json:"assa"`
+	LocA  float64 `json:"loca"`
+	DetRe float64 `json:"detre"`
+	DetPr float64 `json:"detpr"`
+	AssRe float64 `json:"assre"`
+	AssPr float64 `json:"asspr"`
+	//HOTA0 and LocA0 are the values at the lowest threshold (0.05)
+	HOTA0 float64 `json:"hota0"`
+	LocA0 float64 `json:"loca0"`
+}
+
+func hotaAlphas() []float64 {
+	alphas := make([]float64, 19)
+	for a := range alphas {
+		alphas[a] = 0.05 + float64(a)*0.05
+	}
+	return alphas
+}
+
+func evalHOTA(seq sequence) HOTA {
+	alphas := hotaAlphas()
+	na := len(alphas)
+	tp := make([]float64, na)
+	fn := make([]float64, na)
+	fp := make([]float64, na)
+	loc := make([]float64, na)
+
+	if seq.numTrkDets == 0 || seq.numGtDets == 0 {
+		for a := range alphas {
+			fn[a] = float64(seq.numGtDets)
+			fp[a] = float64(seq.numTrkDets)
+			loc[a] = 1
+		}
+		return summarizeHOTA(tp, fn, fp, loc, make([]float64, na), make([]float64, na), make([]float64, na))
+	}
+
+	ng := seq.numGtIDs
+	nt := seq.numTrkIDs
+	potentialMatches := zeros(ng, nt)
+	gtIDCount := make([]float64, ng)
+	trkIDCount := make([]float64, nt)
+	for t := 0; t < seq.numTimestep; t++ {
+		sim := seq.similarity[t]
+		gtIDs := seq.gtIDs[t]
+		trkIDs := seq.trackerIDs[t]
+		rowSum := make([]float64, len(gtIDs))
+		colSum := make([]float64, len(trkIDs))
+		for i := range gtIDs {
+			for j := range trkIDs {
+				rowSum[i] = rowSum[i] + sim[i][j]
+				colSum[j] = colSum[j] + sim[i][j]
+			}
+		}
+		for i, g := range gtIDs {
+			for j, tr := range trkIDs {
+				denom := rowSum[i] + colSum[j] - sim[i][j]
+				if denom > eps {
+					potentialMatches[g][tr] = potentialMatches[g][tr] + sim[i][j]/denom
+				}
+			}
+			gtIDCount[g]++
+		}
+		for _, tr := range trkIDs {
+			trkIDCount[tr]++
+		}
+	}
+
+	globalAlignment := zeros(ng, nt)
+	for g := 0; g < ng; g++ {
+		for tr := 0; tr < nt; tr++ {
+			globalAlignment[g][tr] = potentialMatches[g][tr] / (gtIDCount[g] + trkIDCount[tr] - potentialMatches[g][tr])
+		}
+	}
+
+	matchesCounts := make([][][]float64, na)
+	for a := range alphas {
+		matchesCounts[a] = zeros(ng, nt)
+	}
+	for t := 0; t < seq.numTimestep; t++ {
+		gtIDs := seq.gtIDs[t]
+		trkIDs := seq.trackerIDs[t]
+		if len(gtIDs) == 0 || len(trkIDs) == 0 {
+			for a := range alphas {
+				fn[a] = fn[a] + float64(len(gtIDs))
+				fp[a] = fp[a] + float64(len(trkIDs))
+			}
+			continue
+		}
+		sim := seq.similarity[t]
+		score := make([][]float64, len(gtIDs))
+		for i, g := range gtIDs {
+			score[i] = make([]float64, len(trkIDs))
+			for j, tr := range trkIDs {
+				score[i][j] = globalAlignment[g][tr] * sim[i][j]
+			}
+		}
+		matches := maximize(score)
+		for a, alpha := range alphas {
+			n := 0.0
+			for _, m := range matches {
+				s := sim[m[0]][m[1]]
+				if s < alpha-eps {
+					continue
+				}
+				n++
+				loc[a] = loc[a] + s
+				matchesCounts[a][gtIDs[m[0]]][trkIDs[m[1]]]++
+			}
+			tp[a] = tp[a] + n
+			fn[a] = fn[a] + float64(len(gtIDs)) - n
+			fp[a] = fp[a] + float64(len(trkIDs)) - n
+		}
+	}
+
+	assA := make([]float64, na)
+	assRe := make([]float64, na)
+	assPr := make([]float64, na)
+	for a := range alphas {
+		for g := 0; g < ng; g++ {
+			for tr := 0; tr < nt; tr++ {
+				mc := matchesCounts[a][g][tr]
+				assA[a] = assA[a] + mc*mc/math.Max(1, gtIDCount[g]+trkIDCount[tr]-mc)
+				assRe[a] = assRe[a] + mc*mc/math.Max(1, gtIDCount[g])
+				assPr[a] = assPr[a] + mc*mc/math.Max(1, trkIDCount[tr])
+			}
+		}
+		assA[a] = assA[a] / math.Max(1, tp[a])
+		assRe[a] = assRe[a] / math.Max(1, tp[a])
+		assPr[a] = assPr[a] / math.Max(1, tp[a])
+		loc[a] = math.Max(1e-10, loc[a]) / math.Max(1e-10, tp[a])
+	}
+	return summarizeHOTA(tp, fn, fp, loc, assA, assRe, assPr)
+}
+
+func summarizeHOTA(tp, fn, fp, loc, assA, assRe, assPr []float64) HOTA {
+	r := HOTA{}
+	na := float64(len(tp))
+	for a := range tp {
+		detA := tp[a] / math.Max(1, tp[a]+fn[a]+fp[a])
+		hota := math.Sqrt(detA * assA[a])
+		if a == 0 {
+			r.HOTA0 = hota
+			r.LocA0 = loc[a]
+		}
+		r.HOTA = r.HOTA + hota/na
+		r.DetA = r.DetA + detA/na
+		r.AssA = r.AssA + assA[a]/na
+		r.LocA = r.LocA + loc[a]/na
+		r.DetRe = r.DetRe + tp[a]/math.Max(1, tp[a]+fn[a])/na
+		r.DetPr = r.DetPr + tp[a]/math.Max(1, tp[a]+fp[a])/na
+		r.AssRe = r.AssRe + assRe[a]/na
+		r.AssPr = r.AssPr + assPr[a]/na
+	}
+	return r
+}
+
+func zeros(rows int, cols int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
@@ -0,0 +1,97 @@
+package eval
+
+//Identity holds the ID metrics, which measure how long each ground truth object keeps the same tracker ID
+type Identity struct {
+	IDF1 float64 `json:"idf1"`
+	IDP  float64 `json:"idp"`
+	IDR  float64 `json:"idr"`
+	IDTP int     `json:"idtp"`
+	IDFP int     `json:"idfp"`
+	IDFN int     `json:"idfn"`
+}
+
+const identityThreshold = 0.5
+
+func evalIdentity(seq sequence) Identity {
+	r := Identity{}
+	if seq.numTrkDets == 0 {
+		r.IDFN = seq.numGtDets
+		return r
+	}
+	if seq.numGtDets == 0 {
+		r.IDFP = seq.numTrkDets
+		return r
+	}
+
+	ng := seq.numGtIDs
+	nt := seq.numTrkIDs
+	potentialMatches := make([][]float64, ng)
+	for g := range potentialMatches {
+		potentialMatches[g] = make([]float64, nt)
+	}
+	gtIDCount := make([]float64, ng)
+	trkIDCount := make([]float64, nt)
+	for t := 0; t < seq.numTimestep; t++ {
+		for i, g := range seq.gtIDs[t] {
+			for j, tr := range seq.trackerIDs[t] {
+				if seq.similarity[t][i][j] >= identityThreshold {
+					potentialMatches[g][tr]++
+				}
+			}
+			gtIDCount[g]++
+		}
+		for _, tr := range seq.trackerIDs[t] {
+			trkIDCount[tr]++
+		}
+	}
+
+	//gt IDs and tracker IDs may also be matched to dummy IDs, which makes all their boxes FN or FP
+	n := ng + nt
+	fp := make([][]float64, n)
+	fn := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		fp[i] = make([]float64, n)
+		fn[i] = make([]float64, n)
+	}
+	for g := 0; g < ng; g++ {
+		for j := nt; j < n; j++ {
+			fn[g][j] = 1e10
+		}
+		for j := 0; j < nt; j++ {
+			fn[g][j] = gtIDCount[g] - potentialMatches[g][j]
+		}
+		fn[g][nt+g] = gtIDCount[g]
+	}
+	for tr := 0; tr < nt; tr++ {
+		for i := ng; i < n; i++ {
+			fp[i][tr] = 1e10
+		}
+		for i := 0; i < ng; i++ {
+			fp[i][tr] = trkIDCount[tr] - potentialMatches[i][tr]
+		}
+		fp[tr+ng][tr] = trkIDCount[tr]
+	}
+
+	cost := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		cost[i] = make([]float64, n)
+		for j := 0; j < n; j++ {
+			cost[i][j] = fp[i][j] + fn[i][j]
+		}
+	}
+	idfn := 0.0
+	idfp := 0.0
+	for _, m := range minimize(cost) {
+		idfn = idfn + fn[m[0]][m[1]]
+		idfp = idfp + fp[m[0]][m[1]]
+	}
+	r.IDFN = int(idfn)
+	r.IDFP = int(idfp)
+	r.IDTP = seq.numGtDets - r.IDFN
+
+	idtp := float64(r.IDTP)
+	r.IDR = idtp / maxf(1, idtp+idfn)
+	r.IDP = idtp / maxf(1, idtp+idfp)
+	r.IDF1 = idtp / maxf(1, idtp+0.5*idfp+0.5*idfn)
+	return r
+}
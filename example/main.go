@@ -36,7 +36,13 @@ func main() {
 	fmt.Printf("PredictsSinceUpdate=%d\n", bt.PredictsSinceUpdate)
 
 	fmt.Printf("Test SORT\n")
-	s := sort.NewSORT(2, 4, 0.3)
+	s, err := sort.NewSORT(
+		sort.WithMaxPredictsWithoutUpdate(2),
+		sort.WithMinUpdatesUsePrediction(4),
+		sort.WithIOUThreshold(0.3))
+	if err != nil {
+		panic(err)
+	}
 
 	fmt.Printf("\n\n11111111111\n")
 	b := [][]float64{
@@ -0,0 +1,112 @@
+package sort
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+//Forecast is the predicted position of a tracker some frames ahead
+type Forecast struct {
+	//Steps is the number of frames after the current one
+	Steps int `json:"steps"`
+	//BBox is in the form [x1,y1,x2,y2]
+	BBox []float64 `json:"bbox"`
+	//Ellipse is the one sigma uncertainty of the box center. Scale its axes by 2.45 for a 95% region.
+	//With motion models on the ground plane it is the uncertainty of the bottom center on the ground plane
+	Ellipse Ellipse `json:"ellipse"`
+}
+
+//Ellipse is an uncertainty region derived from a 2x2 covariance
+type Ellipse struct {
+	CX float64 `json:"cx"`
+	CY float64 `json:"cy"`
+	//SemiMajor and SemiMinor are the axes half lengths in pixels
+	SemiMajor float64 `json:"semiMajor"`
+	SemiMinor float64 `json:"semiMinor"`
+	//Angle is the direction of the major axis in degrees, clockwise from the image x axis
+	Angle float64 `json:"angle"`
+}
+
+//Forecast predicts the tracker position for each of the next steps frames without changing its state.
+//The state and covariance are propagated with the motion model from the last update, so frames the tracker
+//has been coasting are accounted for
+func (k *KalmanBoxTracker) Forecast(steps int) []Forecast {
+	sys, nse, _ := k.MotionModel.System()
+	a := sys.Ad
+
+	//KalmanCtx.P is the covariance predicted one step after the last update
+	x := mat.VecDenseCopyOf(k.KalmanFilter.CurrentState())
+	x.MulVec(a, x)
+	k.MotionModel.Constrain(x)
+	p := mat.DenseCopyOf(k.KalmanCtx.P)
+
+	r := make([]Forecast, 0, steps)
+	for t := 1; t <= k.PredictsSinceUpdate+steps; t++ {
+		if t > 1 {
+			x.MulVec(a, x)
+			k.MotionModel.Constrain(x)
+			var pn mat.Dense
+			pn.Product(a, p, a.T())
+			pn.Add(&pn, nse.Q)
+			p = &pn
+		}
+		if t <= k.PredictsSinceUpdate {
+			continue
+		}
+		bbox := k.MotionModel.ToBox(x)
+		cx, cy := 0.0, 0.0
+		wm, ok := k.MotionModel.(WorldModel)
+		if ok {
+			pos, _ := wm.World(x)
+			cx, cy = pos[0], pos[1]
+		} else if b := imageBox(k.MotionModel, bbox); b != nil {
+			cx, cy = (b[0]+b[2])/2, (b[1]+b[3])/2
+		}
+		r = append(r, Forecast{
+			Steps:   t - k.PredictsSinceUpdate,
+			BBox:    bbox,
+			Ellipse: centerEllipse(sys.C, p, cx, cy),
+		})
+	}
+	return r
+}
+
+//Forecast predicts the positions of the tracks reported by Tracks for each of the next steps frames
+func (s *SORT) Forecast(steps int) map[int64][]Forecast {
+	r := make(map[int64][]Forecast)
+	ids := make(map[int64]bool)
+	for _, t := range s.Tracks() {
+		ids[t.ID] = true
+	}
+	for _, trk := range s.Trackers {
+		if ids[trk.ID] {
+			r[trk.ID] = trk.Forecast(steps)
+		}
+	}
+	return r
+}
+
+//centerEllipse projects the state covariance p to the measurement space with c and returns the ellipse
+//of the first two components, which are the box center
+func centerEllipse(c mat.Matrix, p mat.Matrix, cx, cy float64) Ellipse {
+	var cp, cpc mat.Dense
+	cp.Mul(c, p)
+	cpc.Mul(&cp, c.T())
+	sxx := cpc.At(0, 0)
+	syy := cpc.At(1, 1)
+	sxy := cpc.At(0, 1)
+
+	//eigenvalues of the symmetric 2x2 covariance
+	tr := (sxx + syy) / 2
+	d := math.Sqrt(((sxx-syy)/2)*((sxx-syy)/2) + sxy*sxy)
+	l1 := tr + d
+	l2 := math.Max(tr-d, 0)
+	return Ellipse{
+		CX:        cx,
+		CY:        cy,
+		SemiMajor: math.Sqrt(l1),
+		SemiMinor: math.Sqrt(l2),
+		Angle:     0.5 * math.Atan2(2*sxy, sxx-syy) * 180 / math.Pi,
+	}
+}
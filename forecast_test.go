@@ -0,0 +1,57 @@
+package sort
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestForecast(t *testing.T) {
+	s, err := NewSORT(WithMinUpdatesUsePrediction(1), WithMaxPredictsWithoutUpdate(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//box moves 2px right per frame
+	for f := 0; f < 15; f++ {
+		x := 100 + 2*float64(f)
+		err = s.Update([][]float64{{x, 100, x + 40, 200}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	trk := s.Trackers[0]
+	before := s.Snapshot()
+	fc := trk.Forecast(30)
+	if !reflect.DeepEqual(before, s.Snapshot()) {
+		t.Fatalf("Forecast should not change the tracker state")
+	}
+	if len(fc) != 30 || fc[0].Steps != 1 || fc[29].Steps != 30 {
+		t.Fatalf("unexpected forecast steps")
+	}
+	//last box is at x1=128
+	for _, i := range []int{1, 5, 30} {
+		f := fc[i-1]
+		if math.Abs(f.BBox[0]-(128+2*float64(i))) > 0.5 || math.Abs(f.BBox[3]-200) > 0.5 {
+			t.Errorf("unexpected forecast %d bbox=%v", i, f.BBox)
+		}
+	}
+	if fc[0].Ellipse.SemiMajor <= 0 || fc[29].Ellipse.SemiMajor <= fc[4].Ellipse.SemiMajor || fc[4].Ellipse.SemiMajor <= fc[0].Ellipse.SemiMajor {
+		t.Errorf("uncertainty should grow with steps. %v %v %v", fc[0].Ellipse, fc[4].Ellipse, fc[29].Ellipse)
+	}
+
+	if sf := s.Forecast(5); len(sf) != 1 || len(sf[trk.ID]) != 5 {
+		t.Errorf("unexpected session forecast %v", sf)
+	}
+
+	//coasting frames are accounted for
+	s.Update([][]float64{})
+	s.Update([][]float64{})
+	fc2 := trk.Forecast(1)
+	if math.Abs(fc2[0].BBox[0]-fc[2].BBox[0]) > 1e-6 {
+		t.Errorf("forecast after coasting 2 frames should match the 3rd step before. %v %v", fc2[0].BBox, fc[2].BBox)
+	}
+
+	if len(s.Forecast(5)) != 0 {
+		t.Errorf("session forecast should only have reported tracks")
+	}
+}
@@ -0,0 +1,61 @@
+package sort
+
+import (
+	"math"
+)
+
+//ClipBox limits bbox to a frame of width x height pixels
+func ClipBox(bbox []float64, width, height float64) []float64 {
+	r := make([]float64, len(bbox))
+	copy(r, bbox)
+	r[0] = math.Min(math.Max(bbox[0], 0), width)
+	r[1] = math.Min(math.Max(bbox[1], 0), height)
+	r[2] = math.Min(math.Max(bbox[2], 0), width)
+	r[3] = math.Min(math.Max(bbox[3], 0), height)
+	return r
+}
+
+//OutsideFrame tells whether bbox lies entirely outside a frame of width x height pixels
+func OutsideFrame(bbox []float64, width, height float64) bool {
+	return bbox[2] <= 0 || bbox[3] <= 0 || bbox[0] >= width || bbox[1] >= height
+}
+
+//CrossesFrame tells whether part of bbox is outside a frame of width x height pixels
+func CrossesFrame(bbox []float64, width, height float64) bool {
+	return bbox[0] < 0 || bbox[1] < 0 || bbox[2] > width || bbox[3] > height
+}
+
+//HasFrame tells whether the frame size is known
+func (c Config) HasFrame() bool {
+	return c.FrameWidth > 0 && c.FrameHeight > 0
+}
+
+//clip limits an axis aligned bbox to the session frame, if known
+func (c Config) clip(m MotionModel, bbox []float64) []float64 {
+	if !c.HasFrame() || boxSize(m) != 4 {
+		return bbox
+	}
+	return ClipBox(bbox, c.FrameWidth, c.FrameHeight)
+}
+
+//EnclosingBoxModel is implemented by BoxModels of boxes on the image, so that frame borders and calibrations work with them
+type EnclosingBoxModel interface {
+	//EnclosingBox returns the smallest [x1,y1,x2,y2] box containing bbox
+	EnclosingBox(bbox []float64) []float64
+}
+
+//imageBox returns bbox in the form [x1,y1,x2,y2], or nil when the boxes of m are not on the image
+func imageBox(m MotionModel, bbox []float64) []float64 {
+	switch bm := m.(type) {
+	case EnclosingBoxModel:
+		return bm.EnclosingBox(bbox)
+	case BoxModel:
+		return nil
+	}
+	return bbox
+}
+
+//predictedBox returns the image box of the last prediction of trk without changing it, or nil when it is not on the image
+func predictedBox(trk *KalmanBoxTracker) []float64 {
+	return imageBox(trk.MotionModel, trk.MotionModel.ToBox(trk.KalmanCtx.X))
+}
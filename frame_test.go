@@ -0,0 +1,77 @@
+package sort
+
+import (
+	"testing"
+)
+
+func TestFrameExit(t *testing.T) {
+	s, err := NewSORT(WithPreset("pedestrian"), WithMaxPredictsWithoutUpdate(50), WithFrameSize(640, 480))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id int64
+	exitingAt := 0
+	for f := 0; f < 12; f++ {
+		//detectors clip boxes to the image
+		x := 400 + 20*float64(f)
+		err = s.Update([][]float64{ClipBox([]float64{x, 100, x + 100, 300, 0.9}, 640, 480)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracks := s.Tracks()
+		if f < 3 {
+			continue
+		}
+		if len(tracks) != 1 {
+			t.Fatalf("object should be tracked while leaving the frame. frame=%d tracks=%d", f, len(tracks))
+		}
+		if id == 0 {
+			id = tracks[0].ID
+		}
+		if tracks[0].ID != id {
+			t.Errorf("ID switch at the frame border. frame=%d", f)
+		}
+		if exitingAt == 0 && tracks[0].Exiting {
+			exitingAt = f
+		}
+	}
+	if exitingAt == 0 || exitingAt > 8 {
+		t.Errorf("track should be flagged as exiting when it reaches the border. frame=%d", exitingAt)
+	}
+
+	//left the frame
+	frames := 0
+	for len(s.Trackers) > 0 {
+		err = s.Update([][]float64{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames++
+		if frames > 50 {
+			break
+		}
+	}
+	if frames > 10 {
+		t.Errorf("tracker predicted outside the frame should be removed. frames=%d", frames)
+	}
+}
+
+func TestClipBox(t *testing.T) {
+	b := ClipBox([]float64{-10, 20, 700, 500, 0.8}, 640, 480)
+	if b[0] != 0 || b[1] != 20 || b[2] != 640 || b[3] != 480 || b[4] != 0.8 {
+		t.Errorf("unexpected clipped box %v", b)
+	}
+	if !OutsideFrame([]float64{650, 10, 700, 50}, 640, 480) || OutsideFrame([]float64{630, 10, 700, 50}, 640, 480) {
+		t.Errorf("wrong outside frame test")
+	}
+	if !CrossesFrame([]float64{630, 10, 700, 50}, 640, 480) || CrossesFrame([]float64{10, 10, 20, 20}, 640, 480) {
+		t.Errorf("wrong frame border test")
+	}
+	if ResizeFromCenter([]float64{0, 0, 100000, 10}, 2)[2] != 150000 {
+		t.Errorf("resized box should not be limited")
+	}
+	_, err := NewSORT(WithFrameSize(-1, 480))
+	if err == nil {
+		t.Errorf("negative frame size should be rejected")
+	}
+}
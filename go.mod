@@ -8,6 +8,7 @@ require (
 	github.com/konimarti/lti v0.0.1
 	github.com/sirupsen/logrus v1.4.2
 	gonum.org/v1/gonum v0.7.0
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 // replace github.com/flaviostutz/kalman => ../../kalman
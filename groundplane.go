@@ -0,0 +1,164 @@
+package sort
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/flaviostutz/kalman"
+	"github.com/konimarti/lti"
+	"gonum.org/v1/gonum/mat"
+)
+
+//WorldModel is implemented by motion models whose state is on the ground plane
+type WorldModel interface {
+	//World returns the ground plane position and velocity per frame of the box bottom center
+	World(x mat.Vector) ([]float64, []float64)
+}
+
+//GroundPlane tracks the bottom center of boxes on the ground plane with constant velocity, so that perspective doesn't
+//distort velocities. State is [X,Y,w,h,vX,vY] where X,Y are ground plane coordinates and w,h the box size in pixels
+type GroundPlane struct {
+	//ProcessNoise scales the process noise covariance Q
+	ProcessNoise float64
+	//Homography maps image pixels to the ground plane and Inverse maps them back
+	Homography Homography
+	Inverse    Homography
+}
+
+//NewGroundPlane creates a ground plane model for a homography from image pixels to ground plane meters
+func NewGroundPlane(h Homography, processNoise float64) (GroundPlane, error) {
+	inv, err := h.Inverse()
+	if err != nil {
+		return GroundPlane{}, err
+	}
+	return GroundPlane{ProcessNoise: processNoise, Homography: h, Inverse: inv}, nil
+}
+
+//Name identifies the model in configurations
+func (m GroundPlane) Name() string {
+	return "ground-plane"
+}
+
+//System returns the discrete linear system, its noise and the initial state covariance.
+//Ground plane noises are for meters, with objects walking or driving at 25 fps
+func (m GroundPlane) System() (lti.Discrete, kalman.Noise, *mat.Dense) {
+	q := m.ProcessNoise
+	sys := lti.Discrete{
+		Ad: mat.NewDense(6, 6, []float64{
+			1, 0, 0, 0, 1, 0,
+			0, 1, 0, 0, 0, 1,
+			0, 0, 1, 0, 0, 0,
+			0, 0, 0, 1, 0, 0,
+			0, 0, 0, 0, 1, 0,
+			0, 0, 0, 0, 0, 1}),
+		Bd: mat.NewDense(6, 6, nil),
+		C: mat.NewDense(4, 6, []float64{
+			1, 0, 0, 0, 0, 0,
+			0, 1, 0, 0, 0, 0,
+			0, 0, 1, 0, 0, 0,
+			0, 0, 0, 1, 0, 0}),
+		D: mat.NewDense(4, 6, nil),
+	}
+	nse := kalman.Noise{
+		Q: mat.NewDense(6, 6, []float64{
+			0.0001 * q, 0, 0, 0, 0, 0,
+			0, 0.0001 * q, 0, 0, 0, 0,
+			0, 0, q, 0, 0, 0,
+			0, 0, 0, q, 0, 0,
+			0, 0, 0, 0, 0.0001 * q, 0,
+			0, 0, 0, 0, 0, 0.0001 * q}),
+		R: mat.NewDense(4, 4, []float64{
+			0.01, 0, 0, 0,
+			0, 0.01, 0, 0,
+			0, 0, 10, 0,
+			0, 0, 0, 10}),
+	}
+	p := mat.NewDense(6, 6, []float64{
+		1, 0, 0, 0, 0, 0,
+		0, 1, 0, 0, 0, 0,
+		0, 0, 10, 0, 0, 0,
+		0, 0, 0, 10, 0, 0,
+		0, 0, 0, 0, 1, 0,
+		0, 0, 0, 0, 0, 1})
+	return sys, nse, p
+}
+
+//ToMeasurement converts a bounding box to the measurement vector [X,Y,w,h]
+func (m GroundPlane) ToMeasurement(bbox []float64) []float64 {
+	x, y := m.Homography.Project((bbox[0]+bbox[2])/2, bbox[3])
+	return []float64{x, y, bbox[2] - bbox[0], bbox[3] - bbox[1]}
+}
+
+//ToBox converts a state vector back to a bounding box on the image
+func (m GroundPlane) ToBox(x mat.Vector) []float64 {
+	u, v := m.Inverse.Project(x.AtVec(0), x.AtVec(1))
+	w, h := x.AtVec(2), x.AtVec(3)
+	return []float64{u - w/2, v - h, u + w/2, v}
+}
+
+//Velocity returns the image velocity in pixels per frame of the box bottom center
+func (m GroundPlane) Velocity(x mat.Vector) []float64 {
+	u0, v0 := m.Inverse.Project(x.AtVec(0), x.AtVec(1))
+	u1, v1 := m.Inverse.Project(x.AtVec(0)+x.AtVec(4), x.AtVec(1)+x.AtVec(5))
+	return []float64{u1 - u0, v1 - v0}
+}
+
+//World returns the ground plane position and velocity per frame of the box bottom center
+func (m GroundPlane) World(x mat.Vector) ([]float64, []float64) {
+	return []float64{x.AtVec(0), x.AtVec(1)}, []float64{x.AtVec(4), x.AtVec(5)}
+}
+
+//ValidBox tells whether a detection can start a tracker. Boxes whose bottom center is on or above the horizon
+//of the homography have no ground plane position
+func (m GroundPlane) ValidBox(bbox []float64) bool {
+	return Area(bbox) >= 1 && m.measurable(bbox)
+}
+
+//measurable tells whether the bottom center of bbox has a ground plane position
+func (m GroundPlane) measurable(bbox []float64) bool {
+	return m.Homography.front((bbox[0]+bbox[2])/2, bbox[3])
+}
+
+//Constrain avoids predicting negative sizes
+func (m GroundPlane) Constrain(x *mat.VecDense) {
+	x.SetVec(2, math.Max(x.AtVec(2), 1))
+	x.SetVec(3, math.Max(x.AtVec(3), 1))
+}
+
+//newMotionModel creates the motion model of a session. Models on the ground plane need the calibration
+func newMotionModel(c Config) (MotionModel, error) {
+	if c.MotionModel != "ground-plane" {
+		return NewMotionModel(c.MotionModel, c.ProcessNoise)
+	}
+	if c.Calibration == nil {
+		return nil, fmt.Errorf("motion model ground-plane needs a calibration")
+	}
+	return NewGroundPlane(c.Calibration.Homography, c.ProcessNoise)
+}
+
+//gated tells whether the bottom centers of two boxes of a motion model are farther than MaxDistance on the ground plane.
+//Boxes without a ground plane position are always gated
+func (c Config) gated(m MotionModel, bbox1 []float64, bbox2 []float64) bool {
+	b1, b2 := imageBox(m, bbox1), imageBox(m, bbox2)
+	if b1 == nil || b2 == nil {
+		return false
+	}
+	h := c.Calibration.Homography
+	if !h.front((b1[0]+b1[2])/2, b1[3]) || !h.front((b2[0]+b2[2])/2, b2[3]) {
+		return true
+	}
+	return c.Calibration.groundDistance(b1, b2) > c.MaxDistance
+}
+
+//groundDistance is the distance on the ground plane between the bottom centers of two boxes
+func (c Calibration) groundDistance(bbox1 []float64, bbox2 []float64) float64 {
+	x1, y1 := c.Homography.Project((bbox1[0]+bbox1[2])/2, bbox1[3])
+	x2, y2 := c.Homography.Project((bbox2[0]+bbox2[2])/2, bbox2[3])
+	return math.Hypot(x2-x1, y2-y1)
+}
+
+//world returns the ground plane position of the bottom center of bbox
+func (c Calibration) world(bbox []float64) []float64 {
+	x, y := c.Homography.Project((bbox[0]+bbox[2])/2, bbox[3])
+	return []float64{x, y}
+}
@@ -0,0 +1,130 @@
+package sort
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGroundPlaneTracking(t *testing.T) {
+	h, err := NewHomography(
+		[][2]float64{{102, 480}, {538, 480}, {410, 210}, {230, 210}},
+		[][2]float64{{0, 0}, {7, 0}, {7, 30}, {0, 30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv, _ := h.Inverse()
+	s, err := NewSORT(WithPreset("pedestrian"), WithMotionModel("ground-plane"), WithMaxDistance(1),
+		WithCalibration(Calibration{Homography: h, FPS: 25}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//walking away from the camera at 1.5m/s, so the image speed falls with the distance
+	ids := map[int64]bool{}
+	for f := 0; f < 300; f++ {
+		wy := 2 + 0.06*float64(f)
+		u, v := inv.Project(3.5, wy)
+		err = s.Update([][]float64{{u - 10, v - 40, u + 10, v, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracks := s.Tracks()
+		if f < 20 {
+			continue
+		}
+		if len(tracks) != 1 {
+			t.Fatalf("expected 1 track at frame %d, got %d", f, len(tracks))
+		}
+		tr := tracks[0]
+		ids[tr.ID] = true
+		if math.Abs(tr.WorldSpeed-1.5) > 0.1 || math.Abs(tr.WorldVelocity[1]-1.5) > 0.1 {
+			t.Errorf("world speed should be constant. frame=%d velocity=%v", f, tr.WorldVelocity)
+		}
+		if math.Abs(tr.World[0]-3.5) > 0.1 || math.Abs(tr.World[1]-wy) > 0.2 {
+			t.Errorf("unexpected world position at frame %d. world=%v expected=%v", f, tr.World, []float64{3.5, wy})
+		}
+		if math.Abs(tr.BBox[3]-v) > 1e-6 {
+			t.Errorf("image box should be reported. bbox=%v", tr.BBox)
+		}
+	}
+	if len(ids) != 1 {
+		t.Errorf("ID switches walking away. ids=%v", ids)
+	}
+
+	fc := s.Trackers[0].Forecast(5)
+	if math.Abs(fc[4].BBox[3]-s.Trackers[0].LastBBox[3]) > 10 || fc[4].Ellipse.SemiMajor > 1 {
+		t.Errorf("unexpected forecast %+v", fc[4])
+	}
+
+	_, err = NewSORT(WithMotionModel("ground-plane"))
+	if err == nil {
+		t.Errorf("ground plane without calibration should be rejected")
+	}
+	_, err = NewSORT(WithMaxDistance(1))
+	if err == nil {
+		t.Errorf("distance gate without calibration should be rejected")
+	}
+}
+
+func TestGroundPlaneHorizon(t *testing.T) {
+	h, err := NewHomography(
+		[][2]float64{{102, 480}, {538, 480}, {410, 210}, {230, 210}},
+		[][2]float64{{0, 0}, {7, 0}, {7, 30}, {0, 30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the horizon is at y=20.16
+	horizon := 1 / -h[7]
+	m, err := NewGroundPlane(h, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.ValidBox([]float64{300, 100, 340, 200}) || m.ValidBox([]float64{300, -40, 340, horizon}) || m.ValidBox([]float64{300, -40, 340, 10}) {
+		t.Errorf("boxes on or above the horizon should be rejected")
+	}
+
+	s, err := NewSORT(WithMotionModel("ground-plane"), WithMinUpdatesUsePrediction(1), WithMaxPredictsWithoutUpdate(3),
+		WithIOUThreshold(0.01), WithCalibration(Calibration{Homography: h, FPS: 25}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := 0; f < 5; f++ {
+		err = s.Update([][]float64{{300, 22, 340, 200 - 2*float64(f), 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	//a detection overlapping the tracked box, with its bottom on the horizon
+	err = s.Update([][]float64{{300, 5, 340, horizon, 0.9}, {300, -60, 340, 10, 0.9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Trackers) != 1 {
+		t.Errorf("detections above the horizon should not start trackers. trackers=%d", len(s.Trackers))
+	}
+	x := s.Trackers[0].KalmanFilter.CurrentState()
+	for i := 0; i < x.Len(); i++ {
+		if math.IsNaN(x.AtVec(i)) || math.IsInf(x.AtVec(i), 0) {
+			t.Fatalf("tracker state should stay finite. state=%v", s.Trackers[0].CurrentState())
+		}
+	}
+}
+
+func TestGroundDistanceGate(t *testing.T) {
+	//100px per meter
+	c := Calibration{Homography: Homography{0.01, 0, 0, 0, 0.01, 0, 0, 0, 1}, FPS: 25}
+	for _, gate := range []float64{0, 0.5} {
+		s, err := NewSORT(WithCalibration(c), WithMaxDistance(gate))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Update([][]float64{{100, 100, 300, 300, 0.9}})
+		//0.6m jump with an IOU of 0.54
+		s.Update([][]float64{{160, 100, 360, 300, 0.9}})
+		if gate == 0 && len(s.Trackers) != 1 {
+			t.Errorf("boxes should be matched without the gate. trackers=%d", len(s.Trackers))
+		}
+		if gate > 0 && (len(s.Tracks()) != 1 || s.Tracks()[0].ID == s.Trackers[0].ID) {
+			t.Errorf("boxes farther than the gate should not be matched")
+		}
+	}
+}
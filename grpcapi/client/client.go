@@ -0,0 +1,81 @@
+//Package client opens tracking streams on a sort gRPC server
+package client
+
+import (
+	"context"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+//Client is a connection to a tracking server
+type Client struct {
+	conn *grpc.ClientConn
+	api  sortpb.TrackerClient
+}
+
+//Dial connects to a server. Without opts the connection is not encrypted
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, api: sortpb.NewTrackerClient(conn)}, nil
+}
+
+//Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+//Stream is a tracking session of a camera
+type Stream struct {
+	stream sortpb.Tracker_TrackClient
+}
+
+//Open starts a tracking session for camera. cfg may be nil to use the server defaults
+func (c *Client) Open(ctx context.Context, camera string, cfg *sortpb.Config) (*Stream, error) {
+	s, err := c.api.Track(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Send(&sortpb.TrackRequest{Request: &sortpb.TrackRequest_Open{Open: &sortpb.Open{Camera: camera, Config: cfg}}})
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{stream: s}, nil
+}
+
+//Send sends the detections of a frame. Frames may be sent ahead of receiving their results
+func (s *Stream) Send(frame int64, timestamp float64, dets []sort.Detection) error {
+	pdets := make([]*sortpb.Detection, len(dets))
+	for i, d := range dets {
+		score := d.Score
+		pdets[i] = &sortpb.Detection{Bbox: d.BBox, Score: &score, Class: d.Class, Embedding: d.Embedding}
+	}
+	return s.stream.Send(&sortpb.TrackRequest{Request: &sortpb.TrackRequest_Frame{Frame: &sortpb.Frame{Frame: frame, Timestamp: timestamp, Detections: pdets}}})
+}
+
+//Recv receives the tracks of the next frame sent
+func (s *Stream) Recv() (*sortpb.TrackedFrame, error) {
+	return s.stream.Recv()
+}
+
+//Track sends a frame and waits for its tracks
+func (s *Stream) Track(frame int64, timestamp float64, dets []sort.Detection) (*sortpb.TrackedFrame, error) {
+	err := s.Send(frame, timestamp, dets)
+	if err != nil {
+		return nil, err
+	}
+	return s.Recv()
+}
+
+//Close ends the session
+func (s *Stream) Close() error {
+	return s.stream.CloseSend()
+}
@@ -0,0 +1,49 @@
+//Command sort-grpc serves SORT tracking over gRPC
+//
+//    sort-grpc --listen :9090 --preset pedestrian
+package main
+
+import (
+	"flag"
+	"net"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	listen := flag.String("listen", ":9090", "gRPC listen address")
+	preset := flag.String("preset", "", "Default tracker parameters preset: pedestrian, vehicle, drone or point")
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warning or error")
+	flag.Parse()
+
+	level, err := logrus.ParseLevel(*logLevel)
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+	logrus.SetLevel(level)
+
+	opts := []sort.Option{}
+	if *preset != "" {
+		opts = append(opts, sort.WithPreset(*preset))
+	}
+	srv, err := grpcapi.NewServer(opts...)
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+	gs := grpc.NewServer()
+	sortpb.RegisterTrackerServer(gs, srv)
+	logrus.Infof("Listening on %s", *listen)
+	err = gs.Serve(lis)
+	if err != nil {
+		logrus.Fatalf("%s", err)
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/flaviostutz/sort/grpcapi
+
+go 1.24.0
+
+require (
+	github.com/flaviostutz/sort v0.0.0
+	github.com/sirupsen/logrus v1.4.2
+	google.golang.org/grpc v1.74.3
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	github.com/cpmech/gosl v1.1.1 // indirect
+	github.com/flaviostutz/kalman v1.1.0 // indirect
+	github.com/konimarti/lti v0.0.1 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v1.0.1 // indirect
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	gonum.org/v1/gonum v0.7.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260120221211-b8f7ae30c516 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
+)
+
+replace github.com/flaviostutz/sort => ../
@@ -0,0 +1,164 @@
+package grpcapi_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi/grpctest"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func eventTypes(f *sortpb.TrackedFrame) []sortpb.Event_Type {
+	r := make([]sortpb.Event_Type, len(f.Events))
+	for i, e := range f.Events {
+		r[i] = e.Type
+	}
+	return r
+}
+
+func hasEvent(f *sortpb.TrackedFrame, t sortpb.Event_Type) bool {
+	for _, e := range f.Events {
+		if e.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTrackStream(t *testing.T) {
+	h, err := grpctest.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	min := int32(2)
+	maxp := int32(2)
+	s, err := h.Client.Open(context.Background(), "cam1", &sortpb.Config{MinUpdatesUsePrediction: &min, MaxPredictsWithoutUpdate: &maxp})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//send all frames ahead, results must come back in order
+	frames := [][]sort.Detection{
+		{{BBox: []float64{10, 10, 50, 50}, Score: 0.9, Class: "person"}},
+		{{BBox: []float64{12, 10, 52, 50}, Score: 0.9, Class: "person"}},
+		{{BBox: []float64{14, 10, 54, 50}, Score: 0.9, Class: "person"}},
+		{},
+		{{BBox: []float64{18, 10, 58, 50}, Score: 0.9, Class: "person"}},
+		{}, {}, {}, {},
+	}
+	for i, dets := range frames {
+		err = s.Send(int64(i+1), float64(i)*0.04, dets)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]*sortpb.TrackedFrame, 0)
+	for {
+		f, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, f)
+	}
+	if len(results) != len(frames) {
+		t.Fatalf("expected %d frames, got %d", len(frames), len(results))
+	}
+	for i, f := range results {
+		if f.Frame != int64(i+1) || f.Camera != "cam1" {
+			t.Fatalf("frame %d out of order or wrong camera: %v", i+1, f)
+		}
+	}
+
+	if !hasEvent(results[0], sortpb.Event_CREATED) {
+		t.Errorf("frame 1 should create a track. events=%v", eventTypes(results[0]))
+	}
+	confirmed := false
+	for _, f := range results[:3] {
+		confirmed = confirmed || hasEvent(f, sortpb.Event_CONFIRMED)
+	}
+	if !confirmed || len(results[2].Tracks) != 1 || results[2].Tracks[0].Class != "person" {
+		t.Errorf("track should be confirmed by frame 3. tracks=%v", results[2].Tracks)
+	}
+	if !hasEvent(results[3], sortpb.Event_LOST) || len(results[3].Tracks) != 0 {
+		t.Errorf("frame 4 should lose the track. events=%v", eventTypes(results[3]))
+	}
+	if !hasEvent(results[4], sortpb.Event_RECOVERED) {
+		t.Errorf("frame 5 should recover the track. events=%v", eventTypes(results[4]))
+	}
+	deleted := false
+	for _, f := range results[5:] {
+		deleted = deleted || hasEvent(f, sortpb.Event_DELETED)
+	}
+	if !deleted {
+		t.Errorf("track should be deleted after coasting")
+	}
+}
+
+func TestTrackInvalidConfig(t *testing.T) {
+	h, err := grpctest.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	iou := float64(2)
+	s, err := h.Client.Open(context.Background(), "cam1", &sortpb.Config{IouThreshold: &iou})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Recv()
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+}
+
+func TestTrackSharedOptions(t *testing.T) {
+	//server options with spare capacity must not receive the options of a stream
+	opts := make([]sort.Option, 1, 4)
+	opts[0] = sort.WithIOUThreshold(0.3)
+	h, err := grpctest.Start(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	min := int32(1)
+	s, err := h.Client.Open(context.Background(), "cam1", &sortpb.Config{MinUpdatesUsePrediction: &min})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Send(1, 0, []sort.Detection{{BBox: []float64{10, 10, 50, 50}, Score: 0.9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		_, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if opts[:2][1] != nil {
+		t.Errorf("stream options were written into the server options")
+	}
+}
@@ -0,0 +1,52 @@
+//Package grpctest runs a tracking server in process over bufconn, so that clients can be tested without a network
+package grpctest
+
+import (
+	"context"
+	"net"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi"
+	"github.com/flaviostutz/sort/grpcapi/client"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+//Harness is an in process server with a connected client
+type Harness struct {
+	Client   *client.Client
+	server   *grpc.Server
+	listener *bufconn.Listener
+}
+
+//Start serves a grpcapi.Server created with opts
+func Start(opts ...sort.Option) (*Harness, error) {
+	srv, err := grpcapi.NewServer(opts...)
+	if err != nil {
+		return nil, err
+	}
+	lis := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	sortpb.RegisterTrackerServer(gs, srv)
+	go gs.Serve(lis)
+
+	c, err := client.Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		gs.Stop()
+		return nil, err
+	}
+	return &Harness{Client: c, server: gs, listener: lis}, nil
+}
+
+//Close stops the client and the server
+func (h *Harness) Close() {
+	h.Client.Close()
+	h.server.Stop()
+	h.listener.Close()
+}
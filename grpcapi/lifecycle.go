@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	gosort "sort"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+)
+
+//lifecycle finds tracker lifecycle events by comparing the session between frames
+type lifecycle struct {
+	alive     map[int64]bool
+	confirmed map[int64]bool
+	lost      map[int64]bool
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{
+		alive:     make(map[int64]bool),
+		confirmed: make(map[int64]bool),
+		lost:      make(map[int64]bool),
+	}
+}
+
+func (l *lifecycle) update(s *sort.SORT, tracks []sort.Track) []*sortpb.Event {
+	events := make([]*sortpb.Event, 0)
+	add := func(t sortpb.Event_Type, id int64) {
+		events = append(events, &sortpb.Event{Type: t, TrackId: id})
+	}
+
+	alive := make(map[int64]bool)
+	for _, trk := range s.Trackers {
+		alive[trk.ID] = true
+		if !l.alive[trk.ID] {
+			add(sortpb.Event_CREATED, trk.ID)
+		}
+	}
+	reported := make(map[int64]bool)
+	for _, t := range tracks {
+		reported[t.ID] = true
+		if !l.confirmed[t.ID] {
+			l.confirmed[t.ID] = true
+			add(sortpb.Event_CONFIRMED, t.ID)
+		} else if l.lost[t.ID] {
+			delete(l.lost, t.ID)
+			add(sortpb.Event_RECOVERED, t.ID)
+		}
+	}
+	for id := range l.confirmed {
+		if alive[id] && !reported[id] && !l.lost[id] {
+			l.lost[id] = true
+			add(sortpb.Event_LOST, id)
+		}
+	}
+	for id := range l.alive {
+		if !alive[id] {
+			delete(l.confirmed, id)
+			delete(l.lost, id)
+			add(sortpb.Event_DELETED, id)
+		}
+	}
+	l.alive = alive
+
+	gosort.SliceStable(events, func(i, j int) bool {
+		if events[i].Type != events[j].Type {
+			return events[i].Type < events[j].Type
+		}
+		return events[i].TrackId < events[j].TrackId
+	})
+	return events
+}
@@ -0,0 +1,178 @@
+//Package grpcapi serves SORT tracking over a gRPC bidirectional stream, one session per camera stream
+package grpcapi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//Server implements the sortpb.Tracker service
+type Server struct {
+	sortpb.UnimplementedTrackerServer
+	opts []sort.Option
+}
+
+//NewServer creates a server whose sessions use opts. Clients may override them with an Open message
+func NewServer(opts ...sort.Option) (*Server, error) {
+	_, err := sort.NewSORT(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{opts: opts}, nil
+}
+
+//Track runs a tracking session until the client closes its side of the stream
+func (s *Server) Track(stream sortpb.Tracker_TrackServer) error {
+	var session *sort.SORT
+	camera := ""
+	lc := newLifecycle()
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			logrus.Debugf("Tracking stream closed. camera=%s", camera)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch r := req.Request.(type) {
+		case *sortpb.TrackRequest_Open:
+			if session != nil {
+				return status.Error(codes.FailedPrecondition, "open must be the first message of the stream")
+			}
+			//s.opts is shared by all streams, so it must not be appended to in place
+			copts, err := configOptions(r.Open.Config)
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			opts := append(append([]sort.Option{}, s.opts...), copts...)
+			session, err = sort.NewSORT(opts...)
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			camera = r.Open.Camera
+			logrus.Debugf("Tracking stream opened. camera=%s", camera)
+
+		case *sortpb.TrackRequest_Frame:
+			if session == nil {
+				session, err = sort.NewSORT(s.opts...)
+				if err != nil {
+					return status.Error(codes.Internal, err.Error())
+				}
+			}
+			err = session.UpdateDetections(toDetections(r.Frame.Detections))
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			tracks := session.Tracks()
+			err = stream.Send(&sortpb.TrackedFrame{
+				Frame:     r.Frame.Frame,
+				Timestamp: r.Frame.Timestamp,
+				Camera:    camera,
+				Tracks:    fromTracks(tracks),
+				Events:    lc.update(session, tracks),
+			})
+			if err != nil {
+				return err
+			}
+
+		default:
+			return status.Error(codes.InvalidArgument, "empty request")
+		}
+	}
+}
+
+//configOptions converts the fields set in c to session options
+func configOptions(c *sortpb.Config) ([]sort.Option, error) {
+	opts := make([]sort.Option, 0)
+	if c == nil {
+		return opts, nil
+	}
+	if c.Preset != "" {
+		opts = append(opts, sort.WithPreset(c.Preset))
+	}
+	if c.MaxPredictsWithoutUpdate != nil {
+		opts = append(opts, sort.WithMaxPredictsWithoutUpdate(int(*c.MaxPredictsWithoutUpdate)))
+	}
+	if c.MinUpdatesUsePrediction != nil {
+		opts = append(opts, sort.WithMinUpdatesUsePrediction(int(*c.MinUpdatesUsePrediction)))
+	}
+	if c.IouThreshold != nil {
+		opts = append(opts, sort.WithIOUThreshold(*c.IouThreshold))
+	}
+	if c.MotionModel != nil {
+		opts = append(opts, sort.WithMotionModel(*c.MotionModel))
+	}
+	if c.ProcessNoise != nil {
+		opts = append(opts, sort.WithProcessNoise(*c.ProcessNoise))
+	}
+	if c.CostFunction != nil {
+		opts = append(opts, sort.WithCostFunction(*c.CostFunction))
+	}
+	if c.ConfidenceDecay != nil {
+		opts = append(opts, sort.WithConfidenceDecay(*c.ConfidenceDecay))
+	}
+	if c.MinConfidence != nil {
+		opts = append(opts, sort.WithMinConfidence(*c.MinConfidence))
+	}
+	if c.ReportConfidence != nil {
+		opts = append(opts, sort.WithReportConfidence(*c.ReportConfidence))
+	}
+	if c.VelocitySmoothing != nil {
+		opts = append(opts, sort.WithVelocitySmoothing(*c.VelocitySmoothing))
+	}
+	if len(c.KeypointSigmas) > 0 {
+		opts = append(opts, sort.WithKeypointSigmas(c.KeypointSigmas))
+	}
+	if f := c.KeypointSmoothing; f != nil {
+		opts = append(opts, sort.WithKeypointSmoothing(sort.OneEuro{MinCutoff: f.MinCutoff, Beta: f.Beta, DCutoff: f.DCutoff, FPS: f.Fps}))
+	}
+	if c.FrameWidth != nil || c.FrameHeight != nil {
+		opts = append(opts, sort.WithFrameSize(c.GetFrameWidth(), c.GetFrameHeight()))
+	}
+	if c.MaxDistance != nil {
+		opts = append(opts, sort.WithMaxDistance(*c.MaxDistance))
+	}
+	if c.MaxPointDistance != nil {
+		opts = append(opts, sort.WithMaxPointDistance(*c.MaxPointDistance))
+	}
+	if c.ScoreNoise != nil {
+		opts = append(opts, sort.WithScoreNoise(*c.ScoreNoise))
+	}
+	if cal := c.Calibration; cal != nil {
+		if len(cal.Homography) != 9 {
+			return nil, fmt.Errorf("calibration homography must have 9 values")
+		}
+		h := sort.Homography{}
+		copy(h[:], cal.Homography)
+		opts = append(opts, sort.WithCalibration(sort.Calibration{Homography: h, FPS: cal.Fps}))
+	}
+	return opts, nil
+}
+
+func toDetections(dets []*sortpb.Detection) []sort.Detection {
+	r := make([]sort.Detection, len(dets))
+	for i, d := range dets {
+		score := 1.0
+		if d.Score != nil {
+			score = *d.Score
+		}
+		r[i] = sort.Detection{BBox: d.Bbox, Score: score, Class: d.Class, Embedding: d.Embedding}
+	}
+	return r
+}
+
+func fromTracks(tracks []sort.Track) []*sortpb.Track {
+	r := make([]*sortpb.Track, len(tracks))
+	for i, t := range tracks {
+		r[i] = &sortpb.Track{Id: t.ID, Bbox: t.BBox, Score: t.Score, Class: t.Class}
+	}
+	return r
+}
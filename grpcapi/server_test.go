@@ -0,0 +1,49 @@
+package grpcapi
+
+import (
+	"testing"
+
+	"github.com/flaviostutz/sort"
+	"github.com/flaviostutz/sort/grpcapi/sortpb"
+)
+
+func TestToDetections(t *testing.T) {
+	score := 0.0
+	dets := toDetections([]*sortpb.Detection{{Bbox: []float64{0, 0, 10, 10}}, {Bbox: []float64{0, 0, 10, 10}, Score: &score}})
+	if dets[0].Score != 1 {
+		t.Errorf("detections without score should have score 1, got %v", dets[0].Score)
+	}
+	if dets[1].Score != 0 {
+		t.Errorf("score 0 should be kept, got %v", dets[1].Score)
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	decay, width, noise := 0.1, 640.0, "inverse"
+	opts, err := configOptions(&sortpb.Config{
+		ConfidenceDecay:   &decay,
+		FrameWidth:        &width,
+		ScoreNoise:        &noise,
+		KeypointSmoothing: &sortpb.OneEuro{MinCutoff: 1, Beta: 0.1, DCutoff: 1, Fps: 25},
+		Calibration:       &sortpb.Calibration{Homography: []float64{1, 0, 0, 0, 1, 0, 0, 0, 1}, Fps: 25},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := sort.NewSORT(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := s.Config()
+	if c.ConfidenceDecay != decay || c.FrameWidth != width || c.FrameHeight != 0 || c.ScoreNoise != noise {
+		t.Errorf("config fields should be applied. config=%+v", c)
+	}
+	if c.KeypointSmoothing == nil || c.KeypointSmoothing.FPS != 25 || c.Calibration == nil || c.Calibration.Homography[4] != 1 {
+		t.Errorf("config messages should be applied. config=%+v", c)
+	}
+
+	_, err = configOptions(&sortpb.Config{Calibration: &sortpb.Calibration{Homography: []float64{1, 0, 0}, Fps: 25}})
+	if err == nil {
+		t.Errorf("homographies without 9 values should be rejected")
+	}
+}
@@ -0,0 +1,4 @@
+//Package sortpb has the protobuf messages and the gRPC service of the tracking API
+package sortpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative sort.proto
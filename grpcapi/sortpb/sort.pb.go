@@ -0,0 +1,1034 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: sort.proto
+
+package sortpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Event_Type int32
+
+const (
+	Event_TYPE_UNSPECIFIED Event_Type = 0
+	// CREATED is sent when a tracker is created from an unmatched detection
+	Event_CREATED Event_Type = 1
+	// CONFIRMED is sent the first time a tracker is reported in tracks
+	Event_CONFIRMED Event_Type = 2
+	// LOST is sent when a tracker stops being matched to detections
+	Event_LOST Event_Type = 3
+	// RECOVERED is sent when a lost tracker is matched again
+	Event_RECOVERED Event_Type = 4
+	// DELETED is sent when a tracker is removed
+	Event_DELETED Event_Type = 5
+)
+
+// Enum value maps for Event_Type.
+var (
+	Event_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "CREATED",
+		2: "CONFIRMED",
+		3: "LOST",
+		4: "RECOVERED",
+		5: "DELETED",
+	}
+	Event_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"CREATED":          1,
+		"CONFIRMED":        2,
+		"LOST":             3,
+		"RECOVERED":        4,
+		"DELETED":          5,
+	}
+)
+
+func (x Event_Type) Enum() *Event_Type {
+	p := new(Event_Type)
+	*p = x
+	return p
+}
+
+func (x Event_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Event_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_sort_proto_enumTypes[0].Descriptor()
+}
+
+func (Event_Type) Type() protoreflect.EnumType {
+	return &file_sort_proto_enumTypes[0]
+}
+
+func (x Event_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Event_Type.Descriptor instead.
+func (Event_Type) EnumDescriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type TrackRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*TrackRequest_Open
+	//	*TrackRequest_Frame
+	Request       isTrackRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackRequest) Reset() {
+	*x = TrackRequest{}
+	mi := &file_sort_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackRequest) ProtoMessage() {}
+
+func (x *TrackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackRequest.ProtoReflect.Descriptor instead.
+func (*TrackRequest) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TrackRequest) GetRequest() isTrackRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *TrackRequest) GetOpen() *Open {
+	if x != nil {
+		if x, ok := x.Request.(*TrackRequest_Open); ok {
+			return x.Open
+		}
+	}
+	return nil
+}
+
+func (x *TrackRequest) GetFrame() *Frame {
+	if x != nil {
+		if x, ok := x.Request.(*TrackRequest_Frame); ok {
+			return x.Frame
+		}
+	}
+	return nil
+}
+
+type isTrackRequest_Request interface {
+	isTrackRequest_Request()
+}
+
+type TrackRequest_Open struct {
+	Open *Open `protobuf:"bytes,1,opt,name=open,proto3,oneof"`
+}
+
+type TrackRequest_Frame struct {
+	Frame *Frame `protobuf:"bytes,2,opt,name=frame,proto3,oneof"`
+}
+
+func (*TrackRequest_Open) isTrackRequest_Request() {}
+
+func (*TrackRequest_Frame) isTrackRequest_Request() {}
+
+// Open configures the session. Unset fields take the values of the preset or the tracker defaults.
+type Open struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Camera        string                 `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	Config        *Config                `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Open) Reset() {
+	*x = Open{}
+	mi := &file_sort_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Open) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Open) ProtoMessage() {}
+
+func (x *Open) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Open.ProtoReflect.Descriptor instead.
+func (*Open) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Open) GetCamera() string {
+	if x != nil {
+		return x.Camera
+	}
+	return ""
+}
+
+func (x *Open) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+// Config has the fields of sort.Config with the same names and meanings.
+type Config struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Preset                   string                 `protobuf:"bytes,1,opt,name=preset,proto3" json:"preset,omitempty"`
+	MaxPredictsWithoutUpdate *int32                 `protobuf:"varint,2,opt,name=max_predicts_without_update,json=maxPredictsWithoutUpdate,proto3,oneof" json:"max_predicts_without_update,omitempty"`
+	MinUpdatesUsePrediction  *int32                 `protobuf:"varint,3,opt,name=min_updates_use_prediction,json=minUpdatesUsePrediction,proto3,oneof" json:"min_updates_use_prediction,omitempty"`
+	IouThreshold             *float64               `protobuf:"fixed64,4,opt,name=iou_threshold,json=iouThreshold,proto3,oneof" json:"iou_threshold,omitempty"`
+	MotionModel              *string                `protobuf:"bytes,5,opt,name=motion_model,json=motionModel,proto3,oneof" json:"motion_model,omitempty"`
+	ProcessNoise             *float64               `protobuf:"fixed64,6,opt,name=process_noise,json=processNoise,proto3,oneof" json:"process_noise,omitempty"`
+	CostFunction             *string                `protobuf:"bytes,7,opt,name=cost_function,json=costFunction,proto3,oneof" json:"cost_function,omitempty"`
+	ConfidenceDecay          *float64               `protobuf:"fixed64,8,opt,name=confidence_decay,json=confidenceDecay,proto3,oneof" json:"confidence_decay,omitempty"`
+	MinConfidence            *float64               `protobuf:"fixed64,9,opt,name=min_confidence,json=minConfidence,proto3,oneof" json:"min_confidence,omitempty"`
+	ReportConfidence         *float64               `protobuf:"fixed64,10,opt,name=report_confidence,json=reportConfidence,proto3,oneof" json:"report_confidence,omitempty"`
+	VelocitySmoothing        *float64               `protobuf:"fixed64,11,opt,name=velocity_smoothing,json=velocitySmoothing,proto3,oneof" json:"velocity_smoothing,omitempty"`
+	KeypointSigmas           []float64              `protobuf:"fixed64,12,rep,packed,name=keypoint_sigmas,json=keypointSigmas,proto3" json:"keypoint_sigmas,omitempty"`
+	KeypointSmoothing        *OneEuro               `protobuf:"bytes,13,opt,name=keypoint_smoothing,json=keypointSmoothing,proto3" json:"keypoint_smoothing,omitempty"`
+	// frame_width and frame_height are set together, an unset one is 0
+	FrameWidth       *float64     `protobuf:"fixed64,14,opt,name=frame_width,json=frameWidth,proto3,oneof" json:"frame_width,omitempty"`
+	FrameHeight      *float64     `protobuf:"fixed64,15,opt,name=frame_height,json=frameHeight,proto3,oneof" json:"frame_height,omitempty"`
+	MaxDistance      *float64     `protobuf:"fixed64,16,opt,name=max_distance,json=maxDistance,proto3,oneof" json:"max_distance,omitempty"`
+	MaxPointDistance *float64     `protobuf:"fixed64,17,opt,name=max_point_distance,json=maxPointDistance,proto3,oneof" json:"max_point_distance,omitempty"`
+	ScoreNoise       *string      `protobuf:"bytes,18,opt,name=score_noise,json=scoreNoise,proto3,oneof" json:"score_noise,omitempty"`
+	Calibration      *Calibration `protobuf:"bytes,19,opt,name=calibration,proto3" json:"calibration,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_sort_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Config) GetPreset() string {
+	if x != nil {
+		return x.Preset
+	}
+	return ""
+}
+
+func (x *Config) GetMaxPredictsWithoutUpdate() int32 {
+	if x != nil && x.MaxPredictsWithoutUpdate != nil {
+		return *x.MaxPredictsWithoutUpdate
+	}
+	return 0
+}
+
+func (x *Config) GetMinUpdatesUsePrediction() int32 {
+	if x != nil && x.MinUpdatesUsePrediction != nil {
+		return *x.MinUpdatesUsePrediction
+	}
+	return 0
+}
+
+func (x *Config) GetIouThreshold() float64 {
+	if x != nil && x.IouThreshold != nil {
+		return *x.IouThreshold
+	}
+	return 0
+}
+
+func (x *Config) GetMotionModel() string {
+	if x != nil && x.MotionModel != nil {
+		return *x.MotionModel
+	}
+	return ""
+}
+
+func (x *Config) GetProcessNoise() float64 {
+	if x != nil && x.ProcessNoise != nil {
+		return *x.ProcessNoise
+	}
+	return 0
+}
+
+func (x *Config) GetCostFunction() string {
+	if x != nil && x.CostFunction != nil {
+		return *x.CostFunction
+	}
+	return ""
+}
+
+func (x *Config) GetConfidenceDecay() float64 {
+	if x != nil && x.ConfidenceDecay != nil {
+		return *x.ConfidenceDecay
+	}
+	return 0
+}
+
+func (x *Config) GetMinConfidence() float64 {
+	if x != nil && x.MinConfidence != nil {
+		return *x.MinConfidence
+	}
+	return 0
+}
+
+func (x *Config) GetReportConfidence() float64 {
+	if x != nil && x.ReportConfidence != nil {
+		return *x.ReportConfidence
+	}
+	return 0
+}
+
+func (x *Config) GetVelocitySmoothing() float64 {
+	if x != nil && x.VelocitySmoothing != nil {
+		return *x.VelocitySmoothing
+	}
+	return 0
+}
+
+func (x *Config) GetKeypointSigmas() []float64 {
+	if x != nil {
+		return x.KeypointSigmas
+	}
+	return nil
+}
+
+func (x *Config) GetKeypointSmoothing() *OneEuro {
+	if x != nil {
+		return x.KeypointSmoothing
+	}
+	return nil
+}
+
+func (x *Config) GetFrameWidth() float64 {
+	if x != nil && x.FrameWidth != nil {
+		return *x.FrameWidth
+	}
+	return 0
+}
+
+func (x *Config) GetFrameHeight() float64 {
+	if x != nil && x.FrameHeight != nil {
+		return *x.FrameHeight
+	}
+	return 0
+}
+
+func (x *Config) GetMaxDistance() float64 {
+	if x != nil && x.MaxDistance != nil {
+		return *x.MaxDistance
+	}
+	return 0
+}
+
+func (x *Config) GetMaxPointDistance() float64 {
+	if x != nil && x.MaxPointDistance != nil {
+		return *x.MaxPointDistance
+	}
+	return 0
+}
+
+func (x *Config) GetScoreNoise() string {
+	if x != nil && x.ScoreNoise != nil {
+		return *x.ScoreNoise
+	}
+	return ""
+}
+
+func (x *Config) GetCalibration() *Calibration {
+	if x != nil {
+		return x.Calibration
+	}
+	return nil
+}
+
+type OneEuro struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinCutoff     float64                `protobuf:"fixed64,1,opt,name=min_cutoff,json=minCutoff,proto3" json:"min_cutoff,omitempty"`
+	Beta          float64                `protobuf:"fixed64,2,opt,name=beta,proto3" json:"beta,omitempty"`
+	DCutoff       float64                `protobuf:"fixed64,3,opt,name=d_cutoff,json=dCutoff,proto3" json:"d_cutoff,omitempty"`
+	Fps           float64                `protobuf:"fixed64,4,opt,name=fps,proto3" json:"fps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OneEuro) Reset() {
+	*x = OneEuro{}
+	mi := &file_sort_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OneEuro) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OneEuro) ProtoMessage() {}
+
+func (x *OneEuro) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OneEuro.ProtoReflect.Descriptor instead.
+func (*OneEuro) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OneEuro) GetMinCutoff() float64 {
+	if x != nil {
+		return x.MinCutoff
+	}
+	return 0
+}
+
+func (x *OneEuro) GetBeta() float64 {
+	if x != nil {
+		return x.Beta
+	}
+	return 0
+}
+
+func (x *OneEuro) GetDCutoff() float64 {
+	if x != nil {
+		return x.DCutoff
+	}
+	return 0
+}
+
+func (x *OneEuro) GetFps() float64 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
+type Calibration struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// homography has the 9 values of the 3x3 matrix in row order
+	Homography    []float64 `protobuf:"fixed64,1,rep,packed,name=homography,proto3" json:"homography,omitempty"`
+	Fps           float64   `protobuf:"fixed64,2,opt,name=fps,proto3" json:"fps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Calibration) Reset() {
+	*x = Calibration{}
+	mi := &file_sort_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Calibration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Calibration) ProtoMessage() {}
+
+func (x *Calibration) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Calibration.ProtoReflect.Descriptor instead.
+func (*Calibration) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Calibration) GetHomography() []float64 {
+	if x != nil {
+		return x.Homography
+	}
+	return nil
+}
+
+func (x *Calibration) GetFps() float64 {
+	if x != nil {
+		return x.Fps
+	}
+	return 0
+}
+
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Frame         int64                  `protobuf:"varint,1,opt,name=frame,proto3" json:"frame,omitempty"`
+	Timestamp     float64                `protobuf:"fixed64,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Detections    []*Detection           `protobuf:"bytes,3,rep,name=detections,proto3" json:"detections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_sort_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Frame) GetFrame() int64 {
+	if x != nil {
+		return x.Frame
+	}
+	return 0
+}
+
+func (x *Frame) GetTimestamp() float64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Frame) GetDetections() []*Detection {
+	if x != nil {
+		return x.Detections
+	}
+	return nil
+}
+
+type Detection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// bbox is in the form [x1,y1,x2,y2]
+	Bbox []float64 `protobuf:"fixed64,1,rep,packed,name=bbox,proto3" json:"bbox,omitempty"`
+	// score is 1 when not set, as in JSON detections
+	Score         *float64  `protobuf:"fixed64,2,opt,name=score,proto3,oneof" json:"score,omitempty"`
+	Class         string    `protobuf:"bytes,3,opt,name=class,proto3" json:"class,omitempty"`
+	Embedding     []float64 `protobuf:"fixed64,4,rep,packed,name=embedding,proto3" json:"embedding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Detection) Reset() {
+	*x = Detection{}
+	mi := &file_sort_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Detection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Detection) ProtoMessage() {}
+
+func (x *Detection) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Detection.ProtoReflect.Descriptor instead.
+func (*Detection) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Detection) GetBbox() []float64 {
+	if x != nil {
+		return x.Bbox
+	}
+	return nil
+}
+
+func (x *Detection) GetScore() float64 {
+	if x != nil && x.Score != nil {
+		return *x.Score
+	}
+	return 0
+}
+
+func (x *Detection) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *Detection) GetEmbedding() []float64 {
+	if x != nil {
+		return x.Embedding
+	}
+	return nil
+}
+
+type TrackedFrame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Frame         int64                  `protobuf:"varint,1,opt,name=frame,proto3" json:"frame,omitempty"`
+	Timestamp     float64                `protobuf:"fixed64,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Camera        string                 `protobuf:"bytes,3,opt,name=camera,proto3" json:"camera,omitempty"`
+	Tracks        []*Track               `protobuf:"bytes,4,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	Events        []*Event               `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackedFrame) Reset() {
+	*x = TrackedFrame{}
+	mi := &file_sort_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackedFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackedFrame) ProtoMessage() {}
+
+func (x *TrackedFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackedFrame.ProtoReflect.Descriptor instead.
+func (*TrackedFrame) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TrackedFrame) GetFrame() int64 {
+	if x != nil {
+		return x.Frame
+	}
+	return 0
+}
+
+func (x *TrackedFrame) GetTimestamp() float64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TrackedFrame) GetCamera() string {
+	if x != nil {
+		return x.Camera
+	}
+	return ""
+}
+
+func (x *TrackedFrame) GetTracks() []*Track {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *TrackedFrame) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type Track struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Bbox          []float64              `protobuf:"fixed64,2,rep,packed,name=bbox,proto3" json:"bbox,omitempty"`
+	Score         float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	Class         string                 `protobuf:"bytes,4,opt,name=class,proto3" json:"class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Track) Reset() {
+	*x = Track{}
+	mi := &file_sort_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Track) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Track) ProtoMessage() {}
+
+func (x *Track) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Track.ProtoReflect.Descriptor instead.
+func (*Track) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Track) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Track) GetBbox() []float64 {
+	if x != nil {
+		return x.Bbox
+	}
+	return nil
+}
+
+func (x *Track) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Track) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+// Event tells about changes in the lifecycle of a tracker.
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          Event_Type             `protobuf:"varint,1,opt,name=type,proto3,enum=sort.v1.Event_Type" json:"type,omitempty"`
+	TrackId       int64                  `protobuf:"varint,2,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_sort_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_sort_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_sort_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Event) GetType() Event_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Event_TYPE_UNSPECIFIED
+}
+
+func (x *Event) GetTrackId() int64 {
+	if x != nil {
+		return x.TrackId
+	}
+	return 0
+}
+
+var File_sort_proto protoreflect.FileDescriptor
+
+const file_sort_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"sort.proto\x12\asort.v1\"f\n" +
+	"\fTrackRequest\x12#\n" +
+	"\x04open\x18\x01 \x01(\v2\r.sort.v1.OpenH\x00R\x04open\x12&\n" +
+	"\x05frame\x18\x02 \x01(\v2\x0e.sort.v1.FrameH\x00R\x05frameB\t\n" +
+	"\arequest\"G\n" +
+	"\x04Open\x12\x16\n" +
+	"\x06camera\x18\x01 \x01(\tR\x06camera\x12'\n" +
+	"\x06config\x18\x02 \x01(\v2\x0f.sort.v1.ConfigR\x06config\"\xb3\t\n" +
+	"\x06Config\x12\x16\n" +
+	"\x06preset\x18\x01 \x01(\tR\x06preset\x12B\n" +
+	"\x1bmax_predicts_without_update\x18\x02 \x01(\x05H\x00R\x18maxPredictsWithoutUpdate\x88\x01\x01\x12@\n" +
+	"\x1amin_updates_use_prediction\x18\x03 \x01(\x05H\x01R\x17minUpdatesUsePrediction\x88\x01\x01\x12(\n" +
+	"\riou_threshold\x18\x04 \x01(\x01H\x02R\fiouThreshold\x88\x01\x01\x12&\n" +
+	"\fmotion_model\x18\x05 \x01(\tH\x03R\vmotionModel\x88\x01\x01\x12(\n" +
+	"\rprocess_noise\x18\x06 \x01(\x01H\x04R\fprocessNoise\x88\x01\x01\x12(\n" +
+	"\rcost_function\x18\a \x01(\tH\x05R\fcostFunction\x88\x01\x01\x12.\n" +
+	"\x10confidence_decay\x18\b \x01(\x01H\x06R\x0fconfidenceDecay\x88\x01\x01\x12*\n" +
+	"\x0emin_confidence\x18\t \x01(\x01H\aR\rminConfidence\x88\x01\x01\x120\n" +
+	"\x11report_confidence\x18\n" +
+	" \x01(\x01H\bR\x10reportConfidence\x88\x01\x01\x122\n" +
+	"\x12velocity_smoothing\x18\v \x01(\x01H\tR\x11velocitySmoothing\x88\x01\x01\x12'\n" +
+	"\x0fkeypoint_sigmas\x18\f \x03(\x01R\x0ekeypointSigmas\x12?\n" +
+	"\x12keypoint_smoothing\x18\r \x01(\v2\x10.sort.v1.OneEuroR\x11keypointSmoothing\x12$\n" +
+	"\vframe_width\x18\x0e \x01(\x01H\n" +
+	"R\n" +
+	"frameWidth\x88\x01\x01\x12&\n" +
+	"\fframe_height\x18\x0f \x01(\x01H\vR\vframeHeight\x88\x01\x01\x12&\n" +
+	"\fmax_distance\x18\x10 \x01(\x01H\fR\vmaxDistance\x88\x01\x01\x121\n" +
+	"\x12max_point_distance\x18\x11 \x01(\x01H\rR\x10maxPointDistance\x88\x01\x01\x12$\n" +
+	"\vscore_noise\x18\x12 \x01(\tH\x0eR\n" +
+	"scoreNoise\x88\x01\x01\x126\n" +
+	"\vcalibration\x18\x13 \x01(\v2\x14.sort.v1.CalibrationR\vcalibrationB\x1e\n" +
+	"\x1c_max_predicts_without_updateB\x1d\n" +
+	"\x1b_min_updates_use_predictionB\x10\n" +
+	"\x0e_iou_thresholdB\x0f\n" +
+	"\r_motion_modelB\x10\n" +
+	"\x0e_process_noiseB\x10\n" +
+	"\x0e_cost_functionB\x13\n" +
+	"\x11_confidence_decayB\x11\n" +
+	"\x0f_min_confidenceB\x14\n" +
+	"\x12_report_confidenceB\x15\n" +
+	"\x13_velocity_smoothingB\x0e\n" +
+	"\f_frame_widthB\x0f\n" +
+	"\r_frame_heightB\x0f\n" +
+	"\r_max_distanceB\x15\n" +
+	"\x13_max_point_distanceB\x0e\n" +
+	"\f_score_noise\"i\n" +
+	"\aOneEuro\x12\x1d\n" +
+	"\n" +
+	"min_cutoff\x18\x01 \x01(\x01R\tminCutoff\x12\x12\n" +
+	"\x04beta\x18\x02 \x01(\x01R\x04beta\x12\x19\n" +
+	"\bd_cutoff\x18\x03 \x01(\x01R\adCutoff\x12\x10\n" +
+	"\x03fps\x18\x04 \x01(\x01R\x03fps\"?\n" +
+	"\vCalibration\x12\x1e\n" +
+	"\n" +
+	"homography\x18\x01 \x03(\x01R\n" +
+	"homography\x12\x10\n" +
+	"\x03fps\x18\x02 \x01(\x01R\x03fps\"o\n" +
+	"\x05Frame\x12\x14\n" +
+	"\x05frame\x18\x01 \x01(\x03R\x05frame\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x01R\ttimestamp\x122\n" +
+	"\n" +
+	"detections\x18\x03 \x03(\v2\x12.sort.v1.DetectionR\n" +
+	"detections\"x\n" +
+	"\tDetection\x12\x12\n" +
+	"\x04bbox\x18\x01 \x03(\x01R\x04bbox\x12\x19\n" +
+	"\x05score\x18\x02 \x01(\x01H\x00R\x05score\x88\x01\x01\x12\x14\n" +
+	"\x05class\x18\x03 \x01(\tR\x05class\x12\x1c\n" +
+	"\tembedding\x18\x04 \x03(\x01R\tembeddingB\b\n" +
+	"\x06_score\"\xaa\x01\n" +
+	"\fTrackedFrame\x12\x14\n" +
+	"\x05frame\x18\x01 \x01(\x03R\x05frame\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x01R\ttimestamp\x12\x16\n" +
+	"\x06camera\x18\x03 \x01(\tR\x06camera\x12&\n" +
+	"\x06tracks\x18\x04 \x03(\v2\x0e.sort.v1.TrackR\x06tracks\x12&\n" +
+	"\x06events\x18\x05 \x03(\v2\x0e.sort.v1.EventR\x06events\"W\n" +
+	"\x05Track\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04bbox\x18\x02 \x03(\x01R\x04bbox\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\x12\x14\n" +
+	"\x05class\x18\x04 \x01(\tR\x05class\"\xab\x01\n" +
+	"\x05Event\x12'\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x13.sort.v1.Event.TypeR\x04type\x12\x19\n" +
+	"\btrack_id\x18\x02 \x01(\x03R\atrackId\"^\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCREATED\x10\x01\x12\r\n" +
+	"\tCONFIRMED\x10\x02\x12\b\n" +
+	"\x04LOST\x10\x03\x12\r\n" +
+	"\tRECOVERED\x10\x04\x12\v\n" +
+	"\aDELETED\x10\x052D\n" +
+	"\aTracker\x129\n" +
+	"\x05Track\x12\x15.sort.v1.TrackRequest\x1a\x15.sort.v1.TrackedFrame(\x010\x01B,Z*github.com/flaviostutz/sort/grpcapi/sortpbb\x06proto3"
+
+var (
+	file_sort_proto_rawDescOnce sync.Once
+	file_sort_proto_rawDescData []byte
+)
+
+func file_sort_proto_rawDescGZIP() []byte {
+	file_sort_proto_rawDescOnce.Do(func() {
+		file_sort_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sort_proto_rawDesc), len(file_sort_proto_rawDesc)))
+	})
+	return file_sort_proto_rawDescData
+}
+
+var file_sort_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_sort_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_sort_proto_goTypes = []any{
+	(Event_Type)(0),      // 0: sort.v1.Event.Type
+	(*TrackRequest)(nil), // 1: sort.v1.TrackRequest
+	(*Open)(nil),         // 2: sort.v1.Open
+	(*Config)(nil),       // 3: sort.v1.Config
+	(*OneEuro)(nil),      // 4: sort.v1.OneEuro
+	(*Calibration)(nil),  // 5: sort.v1.Calibration
+	(*Frame)(nil),        // 6: sort.v1.Frame
+	(*Detection)(nil),    // 7: sort.v1.Detection
+	(*TrackedFrame)(nil), // 8: sort.v1.TrackedFrame
+	(*Track)(nil),        // 9: sort.v1.Track
+	(*Event)(nil),        // 10: sort.v1.Event
+}
+var file_sort_proto_depIdxs = []int32{
+	2,  // 0: sort.v1.TrackRequest.open:type_name -> sort.v1.Open
+	6,  // 1: sort.v1.TrackRequest.frame:type_name -> sort.v1.Frame
+	3,  // 2: sort.v1.Open.config:type_name -> sort.v1.Config
+	4,  // 3: sort.v1.Config.keypoint_smoothing:type_name -> sort.v1.OneEuro
+	5,  // 4: sort.v1.Config.calibration:type_name -> sort.v1.Calibration
+	7,  // 5: sort.v1.Frame.detections:type_name -> sort.v1.Detection
+	9,  // 6: sort.v1.TrackedFrame.tracks:type_name -> sort.v1.Track
+	10, // 7: sort.v1.TrackedFrame.events:type_name -> sort.v1.Event
+	0,  // 8: sort.v1.Event.type:type_name -> sort.v1.Event.Type
+	1,  // 9: sort.v1.Tracker.Track:input_type -> sort.v1.TrackRequest
+	8,  // 10: sort.v1.Tracker.Track:output_type -> sort.v1.TrackedFrame
+	10, // [10:11] is the sub-list for method output_type
+	9,  // [9:10] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_sort_proto_init() }
+func file_sort_proto_init() {
+	if File_sort_proto != nil {
+		return
+	}
+	file_sort_proto_msgTypes[0].OneofWrappers = []any{
+		(*TrackRequest_Open)(nil),
+		(*TrackRequest_Frame)(nil),
+	}
+	file_sort_proto_msgTypes[2].OneofWrappers = []any{}
+	file_sort_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sort_proto_rawDesc), len(file_sort_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sort_proto_goTypes,
+		DependencyIndexes: file_sort_proto_depIdxs,
+		EnumInfos:         file_sort_proto_enumTypes,
+		MessageInfos:      file_sort_proto_msgTypes,
+	}.Build()
+	File_sort_proto = out.File
+	file_sort_proto_goTypes = nil
+	file_sort_proto_depIdxs = nil
+}
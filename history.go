@@ -0,0 +1,185 @@
+package sort
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+//History keeps the filtered means and covariances of a tracker for every frame of its life, so that
+//trajectories can be refined offline with Smoothed. Frames with a detection record the state of the tracker
+//filter itself. Frames the tracker was coasting are predicted from the previous frame with the motion model
+type History struct {
+	model MotionModel
+	a     *mat.Dense
+	q     *mat.Dense
+	c     *mat.Dense
+	//Steps has one element per frame, starting in the frame the tracker was created
+	Steps []HistoryStep
+}
+
+//HistoryStep is the filter state of a tracker in a frame
+type HistoryStep struct {
+	Frame int
+	//Detection is the matched detection in the form [x1,y1,x2,y2,score] or nil when the tracker was coasting
+	Detection []float64
+	//XPred and PPred are the mean and covariance predicted from the previous frame
+	XPred *mat.VecDense
+	PPred *mat.Dense
+	//X and P are the filtered mean and covariance
+	X *mat.VecDense
+	P *mat.Dense
+}
+
+//Estimate is the state of a tracker in a frame
+type Estimate struct {
+	Frame int
+	BBox  []float64
+	X     *mat.VecDense
+	P     *mat.Dense
+	//Measured is false for frames where the tracker was coasting
+	Measured bool
+	//Score is the score of the detection matched in this frame
+	Score float64
+}
+
+//NewHistory starts the history of trk with its state in frame
+func NewHistory(trk *KalmanBoxTracker, frame int) *History {
+	sys, nse, _ := trk.MotionModel.System()
+	h := &History{model: trk.MotionModel, a: sys.Ad, q: nse.Q, c: sys.C}
+	x, p := h.filtered(trk)
+	h.Steps = append(h.Steps, HistoryStep{
+		Frame:     frame,
+		Detection: trk.LastBBox,
+		XPred:     mat.VecDenseCopyOf(x),
+		PPred:     mat.DenseCopyOf(p),
+		X:         x,
+		P:         p,
+	})
+	return h
+}
+
+//Add records the state of trk in frame. detection is the detection trk was updated with in frame,
+//or nil when it was coasting
+func (h *History) Add(frame int, trk *KalmanBoxTracker, detection []float64) {
+	last := h.Steps[len(h.Steps)-1]
+	for f := last.Frame + 1; f <= frame; f++ {
+		var xp mat.VecDense
+		xp.MulVec(h.a, last.X)
+		var pp mat.Dense
+		pp.Product(h.a, last.P, h.a.T())
+		pp.Add(&pp, h.q)
+
+		step := HistoryStep{Frame: f, XPred: &xp, PPred: &pp, X: mat.VecDenseCopyOf(&xp), P: mat.DenseCopyOf(&pp)}
+		if f == frame && detection != nil {
+			step.Detection = detection
+			step.X, step.P = h.filtered(trk)
+			//angles of the tracker state may have wrapped around since the previous frame
+			h.alignAngles(step.X, &xp)
+		}
+		h.Steps = append(h.Steps, step)
+		last = step
+	}
+}
+
+//filtered returns copies of the filtered mean and covariance of the last update of trk. The filter only keeps
+//the covariance predicted for the next frame, Pp = A P A^T + Q, so P is recovered as A^-1 (Pp - Q) A^-T
+func (h *History) filtered(trk *KalmanBoxTracker) (*mat.VecDense, *mat.Dense) {
+	x := mat.VecDenseCopyOf(trk.KalmanFilter.CurrentState())
+	// A (P A^T) = Pp - Q and, as P is symmetric, A P = (P A^T)^T
+	var d, apt, p mat.Dense
+	d.Sub(trk.KalmanCtx.P, h.q)
+	err := apt.Solve(h.a, &d)
+	if err == nil {
+		err = p.Solve(h.a, apt.T())
+	}
+	if err != nil {
+		return x, mat.DenseCopyOf(trk.KalmanCtx.P)
+	}
+	return x, &p
+}
+
+//alignAngles changes the angles of x to the equivalent values closest to ref, for motion models with angles
+func (h *History) alignAngles(x *mat.VecDense, ref mat.Vector) {
+	am, ok := h.model.(AngleModel)
+	if !ok {
+		return
+	}
+	var z mat.VecDense
+	z.MulVec(h.c, x)
+	zv := mat.VecDenseCopyOf(&z)
+	am.AlignMeasurement(zv.RawVector().Data, ref)
+	// X = X + C^T (Zaligned - C X)
+	zv.SubVec(zv, &z)
+	var dx mat.VecDense
+	dx.MulVec(h.c.T(), zv)
+	x.AddVec(x, &dx)
+}
+
+//Filtered returns the causal estimates of each frame
+func (h *History) Filtered() []Estimate {
+	r := make([]Estimate, len(h.Steps))
+	for i, s := range h.Steps {
+		r[i] = h.estimate(s, s.X, s.P)
+	}
+	return r
+}
+
+//Smoothed runs a Rauch-Tung-Striebel backward pass over the filtered states, so that each estimate
+//also uses the detections that came after it
+func (h *History) Smoothed() []Estimate {
+	n := len(h.Steps)
+	xs := make([]*mat.VecDense, n)
+	ps := make([]*mat.Dense, n)
+	xs[n-1] = h.Steps[n-1].X
+	ps[n-1] = h.Steps[n-1].P
+	for k := n - 2; k >= 0; k-- {
+		cur := h.Steps[k]
+		next := h.Steps[k+1]
+
+		// G = P A^T Pp(k+1)^-1, solved as Pp(k+1)^T G^T = (P A^T)^T
+		var pat, gt mat.Dense
+		pat.Mul(cur.P, h.a.T())
+		err := gt.Solve(next.PPred.T(), pat.T())
+		if err != nil {
+			xs[k] = cur.X
+			ps[k] = cur.P
+			continue
+		}
+		g := gt.T()
+
+		// Xs = X + G (Xs(k+1) - Xp(k+1))
+		var dx, gdx mat.VecDense
+		dx.SubVec(xs[k+1], next.XPred)
+		gdx.MulVec(g, &dx)
+		var x mat.VecDense
+		x.AddVec(cur.X, &gdx)
+
+		// Ps = P + G (Ps(k+1) - Pp(k+1)) G^T
+		var dp, gdpg, p mat.Dense
+		dp.Sub(ps[k+1], next.PPred)
+		gdpg.Product(g, &dp, &gt)
+		p.Add(cur.P, &gdpg)
+
+		xs[k] = &x
+		ps[k] = &p
+	}
+
+	r := make([]Estimate, n)
+	for i, s := range h.Steps {
+		r[i] = h.estimate(s, xs[i], ps[i])
+	}
+	return r
+}
+
+func (h *History) estimate(s HistoryStep, x *mat.VecDense, p *mat.Dense) Estimate {
+	e := Estimate{
+		Frame:    s.Frame,
+		BBox:     h.model.ToBox(x),
+		X:        x,
+		P:        p,
+		Measured: s.Detection != nil,
+	}
+	if s.Detection != nil {
+		e.Score = detectionScore(s.Detection, boxSize(h.model))
+	}
+	return e
+}
@@ -0,0 +1,78 @@
+package sort
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestHistorySmoothed(t *testing.T) {
+	s, err := NewSORT(WithHistory(), WithMaxPredictsWithoutUpdate(3), WithMinUpdatesUsePrediction(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	truth := func(f int) float64 { return 100 + 3*float64(f) }
+	states := make([][]float64, 0)
+	for f := 1; f <= 40; f++ {
+		dets := [][]float64{}
+		//frames 20 and 21 have no detection
+		if f != 20 && f != 21 {
+			x := truth(f) + rnd.NormFloat64()*2
+			dets = append(dets, []float64{x, 100, x + 40, 200, 0.9})
+		}
+		err = s.Update(dets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		states = append(states, s.Trackers[0].CurrentState())
+	}
+	if len(s.Trackers) != 1 || s.Trackers[0].History == nil {
+		t.Fatalf("expected a single tracker with history. trackers=%d", len(s.Trackers))
+	}
+	h := s.Trackers[0].History
+	if len(h.Steps) != 40 || h.Steps[19].Detection != nil || h.Steps[20].Detection != nil {
+		t.Fatalf("history should have one step per frame and coasted steps without detection. steps=%d", len(h.Steps))
+	}
+
+	filtered := h.Filtered()
+	smoothed := h.Smoothed()
+	//the filtered covariance is the one the tracker predicted its next frame from
+	var pp mat.Dense
+	pp.Product(h.a, h.Steps[39].P, h.a.T())
+	pp.Add(&pp, h.q)
+	if !mat.EqualApprox(&pp, s.Trackers[0].KalmanCtx.P, 1e-6) {
+		t.Errorf("wrong filtered covariance")
+	}
+	//measured frames record the filter of the tracker itself
+	for i, e := range filtered {
+		if e.Measured && !equalBBox(e.BBox, states[i]) {
+			t.Fatalf("history should have the tracker state. frame=%d history=%v tracker=%v", e.Frame, e.BBox, states[i])
+		}
+	}
+	var ef, es float64
+	for i := 10; i < 40; i++ {
+		cx := truth(i+1) + 20
+		ef += math.Abs((filtered[i].BBox[0]+filtered[i].BBox[2])/2 - cx)
+		es += math.Abs((smoothed[i].BBox[0]+smoothed[i].BBox[2])/2 - cx)
+	}
+	if es >= ef {
+		t.Errorf("smoothed error should be lower than filtered. filtered=%f smoothed=%f", ef, es)
+	}
+	if smoothed[19].Measured || !smoothed[18].Measured {
+		t.Errorf("unexpected measured flags")
+	}
+	//the smoother uses future detections, so coasted frames get less uncertain
+	if smoothed[20].P.At(0, 0) >= filtered[20].P.At(0, 0) {
+		t.Errorf("smoothed covariance should be lower than filtered while coasting")
+	}
+
+	for f := 1; f <= 5; f++ {
+		s.Update([][]float64{})
+	}
+	if len(s.Trackers) != 0 || len(s.Finished) != 1 {
+		t.Errorf("removed trackers should be kept in Finished. trackers=%d finished=%d", len(s.Trackers), len(s.Finished))
+	}
+}
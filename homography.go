@@ -0,0 +1,104 @@
+package sort
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+//Homography is a 3x3 projective transform in row major order. It usually maps image pixels to ground plane meters
+type Homography [9]float64
+
+//NewHomography estimates the homography that maps src points to dst points from at least 4 correspondences.
+//With more points the least squares solution is returned
+func NewHomography(src, dst [][2]float64) (Homography, error) {
+	if len(src) != len(dst) {
+		return Homography{}, fmt.Errorf("src and dst must have the same number of points")
+	}
+	if len(src) < 4 {
+		return Homography{}, fmt.Errorf("at least 4 point correspondences are needed")
+	}
+	//with h[8]=1, each correspondence gives two linear equations on the other 8 elements
+	n := len(src)
+	a := mat.NewDense(2*n, 8, nil)
+	b := mat.NewVecDense(2*n, nil)
+	for i := 0; i < n; i++ {
+		x, y := src[i][0], src[i][1]
+		u, v := dst[i][0], dst[i][1]
+		a.SetRow(2*i, []float64{x, y, 1, 0, 0, 0, -x * u, -y * u})
+		a.SetRow(2*i+1, []float64{0, 0, 0, x, y, 1, -x * v, -y * v})
+		b.SetVec(2*i, u)
+		b.SetVec(2*i+1, v)
+	}
+	var h mat.VecDense
+	err := h.SolveVec(a, b)
+	if err != nil {
+		return Homography{}, fmt.Errorf("degenerate point correspondences. err=%s", err)
+	}
+	r := Homography{}
+	for i := 0; i < 8; i++ {
+		r[i] = h.AtVec(i)
+	}
+	r[8] = 1
+	return r, nil
+}
+
+//Project maps a point. It returns NaN for points on the horizon of the transform
+func (h Homography) Project(x, y float64) (float64, float64) {
+	w := h[6]*x + h[7]*y + h[8]
+	if w == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return (h[0]*x + h[1]*y + h[2]) / w, (h[3]*x + h[4]*y + h[5]) / w
+}
+
+//front tells whether a point is below the horizon of the transform, the line where w = 0, and maps to a finite point.
+//Points above it are mirrored to bogus positions by Project. As homographies are defined up to their sign, the ground
+//side is the one where w has the sign of h[7], which holds for upright cameras
+func (h Homography) front(x, y float64) bool {
+	w := h[6]*x + h[7]*y + h[8]
+	if w == 0 || w*h[7] < 0 {
+		return false
+	}
+	px, py := h.Project(x, y)
+	return !math.IsNaN(px) && !math.IsNaN(py) && !math.IsInf(px, 0) && !math.IsInf(py, 0)
+}
+
+//Inverse returns the transform that maps points back
+func (h Homography) Inverse() (Homography, error) {
+	var inv mat.Dense
+	err := inv.Inverse(mat.NewDense(3, 3, h[:]))
+	if err != nil {
+		return Homography{}, fmt.Errorf("homography is not invertible")
+	}
+	r := Homography{}
+	copy(r[:], inv.RawMatrix().Data)
+	return r, nil
+}
+
+//Calibration relates image pixels to the ground plane
+type Calibration struct {
+	//Homography maps image points to ground plane points in meters
+	Homography Homography `json:"homography" yaml:"homography"`
+	//FPS is the frame rate, used to convert speeds to meters per second
+	FPS float64 `json:"fps" yaml:"fps"`
+}
+
+//Validate checks that the calibration can be used
+func (c Calibration) Validate() error {
+	if c.FPS <= 0 {
+		return fmt.Errorf("calibration fps must be > 0")
+	}
+	_, err := c.Homography.Inverse()
+	return err
+}
+
+//worldVelocity converts the velocity v in pixels per frame of the bottom center of bbox to meters per second
+func (c Calibration) worldVelocity(bbox []float64, v []float64) []float64 {
+	x := (bbox[0] + bbox[2]) / 2
+	y := bbox[3]
+	x0, y0 := c.Homography.Project(x, y)
+	x1, y1 := c.Homography.Project(x+v[0], y+v[1])
+	return []float64{(x1 - x0) * c.FPS, (y1 - y0) * c.FPS}
+}
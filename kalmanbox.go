@@ -2,9 +2,9 @@ package sort
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/flaviostutz/kalman"
-	"github.com/konimarti/lti"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -20,90 +20,85 @@ type KalmanBoxTracker struct {
 	PredictsSinceUpdate   int
 	UpdatesWithoutPredict int
 	SkipPredicts          int
+	FramesSinceUpdate     int
 	LastBBox              []float64
 	LastBBoxIOU           []float64
 	// history               [][]float64
 	LastResiduals []float64
+	MotionModel   MotionModel
 	KalmanFilter  kalman.Filter
 	KalmanCtrl    *mat.VecDense
 	KalmanCtx     *kalman.Context
+	//Class and Embedding come from the last detection matched to this tracker
+	Class     string
+	Embedding []float64
+	//Velocity is the smoothed box center velocity in pixels per frame. It is nil when the motion model has no velocities
+	Velocity []float64
+	//Confidence rises with matched detection scores and decays while the tracker is coasting. It is between 0 and 1
+	Confidence float64
+	//Keypoints are the joints of the last matched detection, smoothed when the session has KeypointSmoothing
+	Keypoints       []Keypoint
+	keypointFilters [][2]oneEuroState
+	//Mask is the instance mask of the last matched detection that had one
+	Mask *RLE
+	//Exiting is set when the frame size is known and the predicted box crosses the frame border
+	Exiting bool
+	//measurementNoise is the R matrix of KalmanFilter, scaled before each update from baseNoise
+	measurementNoise *mat.Dense
+	baseNoise        *mat.Dense
+	//History is only kept when the session was created WithHistory
+	History *History
 }
 
 //NewKalmanBoxTracker     Initialises a tracker using initial bounding box.
 func NewKalmanBoxTracker(bbox []float64) (KalmanBoxTracker, error) {
-	if len(bbox) < 4 {
-		return KalmanBoxTracker{}, fmt.Errorf("bbox should contain at least 4 positions: x1,y1,x2,y2")
+	return NewKalmanBoxTrackerWithModel(bbox, ConstantVelocity{ProcessNoise: 1})
+}
+
+//NewKalmanBoxTrackerWithModel     Initialises a tracker using initial bounding box and a specific motion model.
+func NewKalmanBoxTrackerWithModel(bbox []float64, model MotionModel) (KalmanBoxTracker, error) {
+	if len(bbox) < boxSize(model) {
+		return KalmanBoxTracker{}, fmt.Errorf("bbox should contain at least %d positions", boxSize(model))
 	}
-	//define constant velocity model
-	kf := kalman.NewFilter(
-		lti.Discrete{
-			Ad: mat.NewDense(7, 7, []float64{
-				1, 0, 0, 0, 1, 0, 0,
-				0, 1, 0, 0, 0, 1, 0,
-				0, 0, 1, 0, 0, 0, 1,
-				0, 0, 0, 1, 0, 0, 0,
-				0, 0, 0, 0, 1, 0, 0,
-				0, 0, 0, 0, 0, 1, 0,
-				0, 0, 0, 0, 0, 0, 1}),
-			Bd: mat.NewDense(7, 7, nil),
-			C: mat.NewDense(4, 7, []float64{
-				1, 0, 0, 0, 0, 0, 0,
-				0, 1, 0, 0, 0, 0, 0,
-				0, 0, 1, 0, 0, 0, 0,
-				0, 0, 0, 1, 0, 0, 0}),
-			D: mat.NewDense(4, 7, nil),
-		},
-		kalman.Noise{
-			Q: mat.NewDense(7, 7, []float64{
-				1, 0, 0, 0, 0, 0, 0,
-				0, 1, 0, 0, 0, 0, 0,
-				0, 0, 1, 0, 0, 0, 0,
-				0, 0, 0, 1, 0, 0, 0,
-				0, 0, 0, 0, 0.01, 0, 0,
-				0, 0, 0, 0, 0, 0.01, 0,
-				0, 0, 0, 0, 0, 0, 0.0001}),
-			R: mat.NewDense(4, 4, []float64{
-				1, 0, 0, 0,
-				0, 1, 0, 0,
-				0, 0, 10, 0,
-				0, 0, 0, 10}),
-		},
-	)
+	sys, nse, p := model.System()
+	kf := kalman.NewFilter(sys, nse)
+
+	zv := model.ToMeasurement(bbox)
+	z := mat.NewVecDense(len(zv), zv)
 
+	//start at the measured state as sort.py does. From a zero state the first update moves part of the
+	//whole distance to the detection into the velocities, so new trackers drift away from their detection
+	n, _ := sys.Ad.Dims()
+	x := mat.NewVecDense(n, nil)
+	x.MulVec(sys.C.T(), z)
 	kctx := kalman.Context{
-		X: mat.NewVecDense(7, []float64{0, 0, 0, 0, 0, 0, 0}),
-		P: mat.NewDense(7, 7, []float64{
-			10, 0, 0, 0, 1, 0, 0,
-			0, 10, 0, 0, 0, 1, 0,
-			0, 0, 10, 0, 0, 0, 1,
-			0, 0, 0, 10, 0, 0, 0,
-			0, 0, 0, 0, 1000, 0, 0,
-			0, 0, 0, 0, 0, 10, 0,
-			0, 0, 0, 0, 0, 0, 10}),
+		X: x,
+		P: p,
 	}
 	// self.M = np.zeros((dim_z, dim_z)) # process-measurement cross correlation
 	// self.K = np.zeros((dim_x, dim_z)) # kalman gain
 	// self.S = np.zeros((dim_z, dim_z)) # system uncertainty
 	// self.SI = np.zeros((dim_z, dim_z)) # inverse system uncertainty
 
-	ctrl := mat.NewVecDense(7, nil)
+	_, nc := sys.Bd.Dims()
+	ctrl := mat.NewVecDense(nc, nil)
 
-	z := mat.NewVecDense(4, convertBBoxToZ(bbox))
 	kf.Apply(&kctx, z, ctrl)
 
-	lastID = lastID + 1
-
 	kbt := KalmanBoxTracker{
-		ID:                    lastID,
+		ID:                    atomic.AddInt64(&lastID, 1),
 		Updates:               0,
 		UpdatesWithoutPredict: 0,
 		Predicts:              0,
 		PredictsSinceUpdate:   0,
 		LastBBox:              bbox,
+		MotionModel:           model,
 		KalmanFilter:          kf,
 		KalmanCtrl:            ctrl,
 		KalmanCtx:             &kctx,
 		LastResiduals:         []float64{-1, -1, -1, -1},
+		measurementNoise:      nse.R,
+		baseNoise:             mat.DenseCopyOf(nse.R),
 		// history:               [][]float64{},
 	}
 
@@ -115,19 +110,29 @@ func NewKalmanBoxTracker(bbox []float64) (KalmanBoxTracker, error) {
 //Update     Updates the state vector with observed bbox
 //Returns the residuals that is the difference between the real value (bbox) and the predicted value
 func (k *KalmanBoxTracker) Update(bbox []float64) ([]float64, error) {
-	if len(bbox) < 4 {
-		return []float64{}, fmt.Errorf("bbox should contain at least 4 positions: x1,y1,x2,y2")
+	n := boxSize(k.MotionModel)
+	if len(bbox) < n {
+		return []float64{}, fmt.Errorf("bbox should contain at least %d positions", n)
 	}
 	k.PredictsSinceUpdate = 0
+	k.FramesSinceUpdate = 0
 	// k.history = [][]float64{}
 	k.Updates = k.Updates + 1
 	k.UpdatesWithoutPredict = k.UpdatesWithoutPredict + 1
 	k.LastBBox = bbox
 
 	cpred := k.CurrentPrediction()
-	residuals := []float64{bbox[0] - cpred[0], bbox[1] - cpred[1], bbox[2] - cpred[2], bbox[3] - cpred[3]}
+	residuals := make([]float64, 0, 4)
+	for i := 0; i < 4 && i < n; i++ {
+		residuals = append(residuals, bbox[i]-cpred[i])
+	}
 
-	z := mat.NewVecDense(4, convertBBoxToZ(bbox))
+	zv := k.MotionModel.ToMeasurement(bbox)
+	am, ok := k.MotionModel.(AngleModel)
+	if ok {
+		am.AlignMeasurement(zv, k.KalmanCtx.X)
+	}
+	z := mat.NewVecDense(len(zv), zv)
 
 	k.KalmanFilter.Apply(k.KalmanCtx, z, k.KalmanCtrl)
 
@@ -138,9 +143,7 @@ func (k *KalmanBoxTracker) Update(bbox []float64) ([]float64, error) {
 func (k *KalmanBoxTracker) PredictNext() []float64 {
 	k.SkipPredicts = 0
 	x := k.KalmanCtx.X
-	if x.AtVec(6)+x.AtVec(2) <= 0 {
-		x.SetVec(6, 0.0)
-	}
+	k.MotionModel.Constrain(x)
 	k.Predicts = k.Predicts + 1
 	if k.PredictsSinceUpdate > 0 {
 		k.UpdatesWithoutPredict = 0
@@ -153,24 +156,34 @@ func (k *KalmanBoxTracker) PredictNext() []float64 {
 	}
 	k.PredictsSinceUpdate = k.PredictsSinceUpdate + 1
 
-	z := []float64{state.AtVec(0), state.AtVec(1), state.AtVec(2), state.AtVec(3)}
 	// k.history = append(k.history, bbox)
-	return convertZToBBox(z)
+	return k.MotionModel.ToBox(state)
+}
+
+//updateVelocity blends the filtered velocity into Velocity. alpha is the weight of the previous value
+func (k *KalmanBoxTracker) updateVelocity(alpha float64) {
+	vm, ok := k.MotionModel.(VelocityModel)
+	if !ok {
+		return
+	}
+	v := vm.Velocity(k.KalmanFilter.CurrentState())
+	if k.Velocity != nil {
+		for i := range v {
+			v[i] = alpha*k.Velocity[i] + (1-alpha)*v[i]
+		}
+	}
+	k.Velocity = v
 }
 
 //CurrentState Returns the current bounding box estimate.
 func (k *KalmanBoxTracker) CurrentState() []float64 {
-	state := k.KalmanFilter.CurrentState()
-	z := []float64{state.AtVec(0), state.AtVec(1), state.AtVec(2), state.AtVec(3)}
-	return convertZToBBox(z)
+	return k.MotionModel.ToBox(k.KalmanFilter.CurrentState())
 }
 
 //CurrentPrediction get last prediction results
 func (k *KalmanBoxTracker) CurrentPrediction() []float64 {
 	k.SkipPredicts = 0
-	state := k.KalmanCtx.X
-	z := []float64{state.AtVec(0), state.AtVec(1), state.AtVec(2), state.AtVec(3)}
-	return convertZToBBox(z)
+	return k.MotionModel.ToBox(k.KalmanCtx.X)
 }
 
 // filter := kalman.NewFilter(
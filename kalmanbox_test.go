@@ -2,6 +2,7 @@ package sort
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"gonum.org/v1/gonum/mat"
@@ -39,6 +40,23 @@ func TestPrediction2(t *testing.T) {
 	bboxEquals(trk.CurrentPrediction(), 100.0, 40.0, w, 30, t)
 }
 
+func TestInitialStateIsMeasured(t *testing.T) {
+	//a still object far from the origin must not start moving because the filter started at zero
+	bbox := []float64{500, 300, 540, 380}
+	trk, err := NewKalmanBoxTracker(bbox)
+	if err != nil {
+		t.Fatalf("Error initializing kalman box tracker %s", err)
+	}
+	for i := 0; i < 5; i++ {
+		pred := trk.PredictNext()
+		for j := range bbox {
+			if math.Abs(pred[j]-bbox[j]) > 1 {
+				t.Fatalf("predicted box drifted from the first detection. step=%d bbox=%v", i, pred)
+			}
+		}
+	}
+}
+
 func bboxEquals(bbox1 []float64, bbox2x, bbox2y, bbox2w, bbox2h float64, t *testing.T) {
 	bbox2 := []float64{bbox2x, bbox2y, bbox2w + bbox2x, bbox2h + bbox2y}
 	b1 := mat.NewVecDense(4, bbox1)
@@ -0,0 +1,145 @@
+package sort
+
+import (
+	"fmt"
+	"math"
+)
+
+//Keypoint is a joint reported by a pose detector. A Score of 0 means the joint was not found
+type Keypoint struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Score float64 `json:"score"`
+}
+
+//COCOSigmas are the per keypoint OKS constants of the 17 COCO body keypoints
+var COCOSigmas = []float64{.026, .025, .025, .035, .035, .079, .079, .072, .072, .062, .062, .107, .107, .087, .087, .089, .089}
+
+//defaultSigma is the mean of COCOSigmas, used for other keypoint sets without sigmas
+const defaultSigma = 0.067
+
+//OKS computes the COCO object keypoint similarity between detected keypoints and reference keypoints of the same
+//skeleton, for an object of the given area. Only keypoints found in both are compared. It is 0 when none is.
+//With nil sigmas, COCOSigmas are used for 17 keypoints and their mean otherwise
+func OKS(det []Keypoint, ref []Keypoint, area float64, sigmas []float64) float64 {
+	if len(det) != len(ref) || area <= 0 {
+		return 0
+	}
+	if sigmas == nil && len(det) == len(COCOSigmas) {
+		sigmas = COCOSigmas
+	}
+	sum := 0.0
+	n := 0
+	for i := range det {
+		if det[i].Score <= 0 || ref[i].Score <= 0 {
+			continue
+		}
+		sigma := defaultSigma
+		if i < len(sigmas) {
+			sigma = sigmas[i]
+		}
+		k := 2 * sigma
+		dx, dy := det[i].X-ref[i].X, det[i].Y-ref[i].Y
+		sum += math.Exp(-(dx*dx + dy*dy) / (2 * area * k * k))
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+//OneEuro configures the One Euro filter, which smooths jitter of slow keypoints while following fast ones without lag
+type OneEuro struct {
+	//MinCutoff is the cutoff frequency in Hz of still keypoints. Lower values remove more jitter
+	MinCutoff float64 `json:"minCutoff" yaml:"minCutoff"`
+	//Beta raises the cutoff frequency with the keypoint speed. Higher values reduce lag
+	Beta float64 `json:"beta" yaml:"beta"`
+	//DCutoff is the cutoff frequency in Hz used to estimate the speed
+	DCutoff float64 `json:"dCutoff" yaml:"dCutoff"`
+	//FPS is the frame rate
+	FPS float64 `json:"fps" yaml:"fps"`
+}
+
+//DefaultOneEuro returns filter parameters for people at 30 fps
+func DefaultOneEuro() OneEuro {
+	return OneEuro{MinCutoff: 1, Beta: 0.01, DCutoff: 1, FPS: 30}
+}
+
+//Validate checks that the parameters can be used
+func (f OneEuro) Validate() error {
+	if f.MinCutoff <= 0 || f.DCutoff <= 0 || f.FPS <= 0 {
+		return fmt.Errorf("one euro minCutoff, dCutoff and fps must be > 0")
+	}
+	if f.Beta < 0 {
+		return fmt.Errorf("one euro beta must be >= 0")
+	}
+	return nil
+}
+
+//alpha is the smoothing factor for a cutoff frequency
+func (f OneEuro) alpha(cutoff float64) float64 {
+	tau := 1 / (2 * math.Pi * cutoff)
+	return 1 / (1 + tau*f.FPS)
+}
+
+//oneEuroState is the state of the filter of one coordinate
+type oneEuroState struct {
+	x       float64
+	dx      float64
+	started bool
+}
+
+func (s *oneEuroState) filter(f OneEuro, x float64) float64 {
+	if !s.started {
+		s.x = x
+		s.started = true
+		return x
+	}
+	dx := (x - s.x) * f.FPS
+	ad := f.alpha(f.DCutoff)
+	s.dx = ad*dx + (1-ad)*s.dx
+	a := f.alpha(f.MinCutoff + f.Beta*math.Abs(s.dx))
+	s.x = a*x + (1-a)*s.x
+	return s.x
+}
+
+//updateKeypoints keeps the keypoints of a matched detection, smoothed with f when not nil.
+//Joints not found keep their last position with a score of 0
+func (k *KalmanBoxTracker) updateKeypoints(kps []Keypoint, f *OneEuro) {
+	if len(kps) == 0 {
+		return
+	}
+	if len(k.Keypoints) != len(kps) {
+		k.Keypoints = make([]Keypoint, len(kps))
+		copy(k.Keypoints, kps)
+		k.keypointFilters = make([][2]oneEuroState, len(kps))
+	}
+	for i, kp := range kps {
+		if kp.Score <= 0 {
+			k.Keypoints[i].Score = 0
+			continue
+		}
+		x, y := kp.X, kp.Y
+		if f != nil {
+			x = k.keypointFilters[i][0].filter(*f, x)
+			y = k.keypointFilters[i][1].filter(*f, y)
+		}
+		k.Keypoints[i] = Keypoint{X: x, Y: y, Score: kp.Score}
+	}
+}
+
+//predictedKeypoints moves the tracker keypoints along with its box to the reference box ref
+func (k *KalmanBoxTracker) predictedKeypoints(ref []float64) []Keypoint {
+	b0, b1 := imageBox(k.MotionModel, k.LastBBox), imageBox(k.MotionModel, ref)
+	if b0 == nil || b1 == nil {
+		return k.Keypoints
+	}
+	dx := (b1[0] + b1[2] - b0[0] - b0[2]) / 2
+	dy := (b1[1] + b1[3] - b0[1] - b0[3]) / 2
+	r := make([]Keypoint, len(k.Keypoints))
+	for i, kp := range k.Keypoints {
+		r[i] = Keypoint{X: kp.X + dx, Y: kp.Y + dy, Score: kp.Score}
+	}
+	return r
+}
@@ -0,0 +1,108 @@
+package sort
+
+import (
+	"math"
+	"testing"
+)
+
+//pose returns 17 keypoints of a person in box x,y (top left) with arms up or down
+func pose(x, y float64, armsUp bool) []Keypoint {
+	kps := make([]Keypoint, 17)
+	for i := range kps {
+		kps[i] = Keypoint{X: x + 50, Y: y + 10*float64(i) + 20, Score: 0.9}
+	}
+	for _, i := range []int{7, 8, 9, 10} {
+		dy := 60.0
+		if armsUp {
+			dy = -60
+		}
+		kps[i].X = x + 10 + 80*float64(i%2)
+		kps[i].Y = y + 100 + dy
+	}
+	return kps
+}
+
+func TestOKS(t *testing.T) {
+	a := pose(0, 0, true)
+	if math.Abs(OKS(a, a, 20000, nil)-1) > 1e-9 {
+		t.Errorf("same keypoints should have OKS 1")
+	}
+	b := pose(0, 0, false)
+	if OKS(a, b, 20000, nil) > 0.8 {
+		t.Errorf("different poses should have lower OKS. oks=%f", OKS(a, b, 20000, nil))
+	}
+	for _, i := range []int{7, 8, 9, 10} {
+		b[i].Score = 0
+	}
+	if math.Abs(OKS(a, b, 20000, nil)-1) > 1e-9 {
+		t.Errorf("keypoints not found should be ignored")
+	}
+	if OKS(a, a[:5], 20000, nil) != 0 {
+		t.Errorf("different skeletons should have OKS 0")
+	}
+}
+
+func TestKeypointTracking(t *testing.T) {
+	s, err := NewSORT(WithCostFunction("oks"), WithIOUThreshold(0.3), WithKeypointSmoothing(DefaultOneEuro()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//two players overlapping almost completely, with boxes jittering more than the distance between them
+	poses := map[int64]bool{}
+	for f := 0; f < 30; f++ {
+		x := 100 + 2*float64(f)
+		j := 6 * float64(f%2)
+		dets := []Detection{
+			{BBox: []float64{x + j, 100, x + j + 100, 300}, Score: 0.9, Keypoints: pose(x, 100, true)},
+			{BBox: []float64{x + 6 - j, 100, x + 106 - j, 300}, Score: 0.9, Keypoints: pose(x+6, 100, false)},
+		}
+		if f%3 == 1 {
+			dets[0], dets[1] = dets[1], dets[0]
+		}
+		err = s.UpdateDetections(dets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tr := range s.Tracks() {
+			up := tr.Keypoints[7].Y < 200
+			prev, ok := poses[tr.ID]
+			if ok && prev != up {
+				t.Fatalf("track switched players at frame %d. id=%d", f, tr.ID)
+			}
+			poses[tr.ID] = up
+		}
+	}
+	if len(poses) != 2 {
+		t.Errorf("expected 2 tracks, got %d", len(poses))
+	}
+
+	//jittering keypoints of a still person
+	s, err = NewSORT(WithKeypointSmoothing(DefaultOneEuro()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxJitter := 0.0
+	for f := 0; f < 30; f++ {
+		kps := pose(100, 100, false)
+		kps[0].X += 4 * float64(f%2*2-1)
+		kps[3].Score = 0
+		err = s.UpdateDetections([]Detection{{BBox: []float64{100, 100, 200, 300}, Score: 0.9, Keypoints: kps}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f > 5 {
+			maxJitter = math.Max(maxJitter, math.Abs(s.Trackers[0].Keypoints[0].X-150))
+		}
+	}
+	if maxJitter > 2 {
+		t.Errorf("keypoints should be smoothed. jitter=%f", maxJitter)
+	}
+	if s.Tracks()[0].Keypoints[3].Score != 0 {
+		t.Errorf("keypoints not found should have score 0")
+	}
+
+	_, err = NewSORT(WithKeypointSmoothing(OneEuro{}))
+	if err == nil {
+		t.Errorf("invalid one euro filter should be rejected")
+	}
+}
@@ -0,0 +1,92 @@
+package kitti
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/flaviostutz/sort"
+)
+
+func TestRead(t *testing.T) {
+	data := `0 0 Car 0 0 -1.79 296.7 161.7 455.2 292.3 2.0 1.8 4.4 -4.6 1.7 13.4 -2.1
+0 -1 DontCare -1 -1 -10 219.3 188.5 245.5 218.3 -1000 -1000 -1000 -10 -1 -1 -1
+
+1 1 Pedestrian 0 0 0.2 100 150 130 250 1.7 0.6 0.8 2.1 1.6 9.0 0.3 0.75
+`
+	objs, err := Read(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 3 || objs[0].Type != "Car" || objs[0].Dimensions[2] != 4.4 || objs[0].Score != 1 || objs[2].Score != 0.75 {
+		t.Fatalf("unexpected objects %+v", objs)
+	}
+
+	b := objs[0].Box()
+	if b[0] != 13.4 || b[1] != 4.6 || math.Abs(b[2]-(-0.7)) > 1e-9 || b[3] != 4.4 || b[4] != 1.8 || b[5] != 2.0 {
+		t.Errorf("unexpected box %v", b)
+	}
+	var o Object
+	o.SetBox(b)
+	if math.Abs(o.Location[1]-1.7) > 1e-9 || math.Abs(o.RotationY-(-2.1)) > 1e-9 || o.Dimensions != objs[0].Dimensions {
+		t.Errorf("box conversion is not reversible %+v", o)
+	}
+
+	frames := Detections(objs)
+	if len(frames) != 2 || len(frames[0]) != 1 || len(frames[1]) != 1 || frames[1][0][7] != 0.75 {
+		t.Errorf("unexpected detections %v", frames)
+	}
+	if len(Detections(objs, "car")[1]) != 0 {
+		t.Errorf("types should filter detections")
+	}
+
+	_, err = ParseObject("0 0 Car 0 0")
+	if err == nil {
+		t.Errorf("short line should be rejected")
+	}
+}
+
+func TestTrack3D(t *testing.T) {
+	s, err := sort.NewSORT(sort.WithMotionModel("constant-velocity-3d"), sort.WithCostFunction("iou-3d"), sort.WithIOUThreshold(0.1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//two cars driving side by side along a curve, a detector flipping the heading of one of them
+	ids := map[int]map[int64]bool{0: {}, 1: {}}
+	for f := 0; f < 30; f++ {
+		yaw := 0.02 * float64(f)
+		dets := make([][]float64, 0)
+		for c := 0; c < 2; c++ {
+			o := Object{Dimensions: [3]float64{1.5, 1.8, 4.5}, Location: [3]float64{-3 * float64(c), 1.6, 10 + float64(f)}, RotationY: -math.Pi/2 - yaw}
+			if c == 1 && f%3 == 0 {
+				o.RotationY += math.Pi
+			}
+			dets = append(dets, append(o.Box(), 0.9))
+		}
+		err = s.Update(dets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tr := range s.Tracks() {
+			if len(tr.BBox) != 7 || tr.Score != 0.9 {
+				t.Fatalf("unexpected track %+v", tr)
+			}
+			c := int(math.Round(tr.BBox[1] / 3))
+			ids[c][tr.ID] = true
+		}
+	}
+	if len(ids[0]) != 1 || len(ids[1]) != 1 {
+		t.Errorf("each car should keep its ID. ids=%v", ids)
+	}
+	for _, trk := range s.Trackers {
+		yaw := trk.MotionModel.ToBox(trk.KalmanFilter.CurrentState())[6]
+		//flipped headings are the same box
+		d := math.Mod(math.Abs(yaw-0.58), math.Pi)
+		if d > 0.1 && d < math.Pi-0.1 {
+			t.Errorf("yaw should follow the curve. yaw=%f", yaw)
+		}
+		if math.Abs(trk.Velocity[0]-1) > 0.1 {
+			t.Errorf("unexpected velocity %v", trk.Velocity)
+		}
+	}
+}
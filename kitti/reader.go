@@ -0,0 +1,159 @@
+//Package kitti reads KITTI tracking label and detection files (label_02 format) and converts their 3D boxes
+//to the [x,y,z,l,w,h,yaw] boxes tracked by the constant-velocity-3d motion model
+package kitti
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//Object is one line of a KITTI tracking file
+type Object struct {
+	Frame int
+	//TrackID is -1 in detection files and for DontCare objects
+	TrackID int64
+	//Type is the object class, e.g. Car, Pedestrian, Cyclist or DontCare
+	Type      string
+	Truncated float64
+	Occluded  int
+	Alpha     float64
+	//BBox is the 2D box on the image in the form [x1,y1,x2,y2]
+	BBox []float64
+	//Dimensions is height, width and length in meters
+	Dimensions [3]float64
+	//Location is the bottom center of the box in camera coordinates (x right, y down, z forward)
+	Location [3]float64
+	//RotationY is the rotation around the camera y axis in radians
+	RotationY float64
+	//Score is the detection confidence. It is 1 when not present
+	Score float64
+}
+
+//ReadFile parses a KITTI tracking file
+func ReadFile(file string) ([]Object, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+//Read parses KITTI tracking lines in the form
+//frame track_id type truncated occluded alpha x1 y1 x2 y2 h w l x y z rotation_y [score]. Empty lines are ignored
+func Read(r io.Reader) ([]Object, error) {
+	objs := make([]Object, 0)
+	scanner := bufio.NewScanner(r)
+	ln := 0
+	for scanner.Scan() {
+		ln = ln + 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		o, err := ParseObject(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", ln, err)
+		}
+		objs = append(objs, o)
+	}
+	return objs, scanner.Err()
+}
+
+//ParseObject parses a single KITTI tracking line
+func ParseObject(line string) (Object, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 17 {
+		return Object{}, fmt.Errorf("expected at least 17 fields, found %d", len(fields))
+	}
+	v := make([]float64, len(fields))
+	for i, f := range fields {
+		if i == 2 {
+			continue
+		}
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return Object{}, fmt.Errorf("invalid field %d %q", i+1, f)
+		}
+		v[i] = n
+	}
+	o := Object{
+		Frame:      int(v[0]),
+		TrackID:    int64(v[1]),
+		Type:       fields[2],
+		Truncated:  v[3],
+		Occluded:   int(v[4]),
+		Alpha:      v[5],
+		BBox:       []float64{v[6], v[7], v[8], v[9]},
+		Dimensions: [3]float64{v[10], v[11], v[12]},
+		Location:   [3]float64{v[13], v[14], v[15]},
+		RotationY:  v[16],
+		Score:      1,
+	}
+	if len(v) > 17 {
+		o.Score = v[17]
+	}
+	return o, nil
+}
+
+//Box returns the 3D box [x,y,z,l,w,h,yaw] in coordinates with x forward, y left and z up,
+//with the box center at x,y,z and yaw around z from the x axis
+func (o Object) Box() []float64 {
+	h, w, l := o.Dimensions[0], o.Dimensions[1], o.Dimensions[2]
+	return []float64{o.Location[2], -o.Location[0], -o.Location[1] + h/2, l, w, h, wrap(-o.RotationY - math.Pi/2)}
+}
+
+//SetBox sets Location, Dimensions and RotationY from a 3D box returned by Box
+func (o *Object) SetBox(b []float64) {
+	o.Dimensions = [3]float64{b[5], b[4], b[3]}
+	o.Location = [3]float64{-b[1], -b[2] + b[5]/2, b[0]}
+	o.RotationY = wrap(-b[6] - math.Pi/2)
+}
+
+//Detections groups objects by frame as SORT detections [x,y,z,l,w,h,yaw,score], from frame 0 to the last one.
+//Only objects of the given types are kept, or all but DontCare when no types are given
+func Detections(objs []Object, types ...string) [][][]float64 {
+	last := -1
+	for _, o := range objs {
+		if o.Frame > last {
+			last = o.Frame
+		}
+	}
+	frames := make([][][]float64, last+1)
+	for i := range frames {
+		frames[i] = make([][]float64, 0)
+	}
+	for _, o := range objs {
+		if !keep(o.Type, types) {
+			continue
+		}
+		frames[o.Frame] = append(frames[o.Frame], append(o.Box(), o.Score))
+	}
+	return frames
+}
+
+func keep(t string, types []string) bool {
+	if len(types) == 0 {
+		return t != "DontCare"
+	}
+	for _, v := range types {
+		if strings.EqualFold(v, t) {
+			return true
+		}
+	}
+	return false
+}
+
+//wrap returns the equivalent angle in [-pi,pi)
+func wrap(a float64) float64 {
+	a = math.Mod(a+math.Pi, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a - math.Pi
+}
@@ -0,0 +1,257 @@
+package sort
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+//RLE is a binary instance mask, run length encoded as in the COCO format. Counts alternate runs of 0 and 1
+//pixels, starting with 0, scanning the mask column by column
+type RLE struct {
+	//Size is [height,width]
+	Size [2]int
+	//Counts are the run lengths
+	Counts []int
+}
+
+//EncodeMask run length encodes a mask of height x width pixels given row by row (m[y*width+x])
+func EncodeMask(m []bool, height, width int) (RLE, error) {
+	if height < 0 || width < 0 || len(m) != height*width {
+		return RLE{}, fmt.Errorf("mask should have height*width pixels")
+	}
+	r := RLE{Size: [2]int{height, width}, Counts: []int{}}
+	v := false
+	n := 0
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			if m[y*width+x] != v {
+				r.Counts = append(r.Counts, n)
+				v = !v
+				n = 0
+			}
+			n++
+		}
+	}
+	r.Counts = append(r.Counts, n)
+	return r, nil
+}
+
+//Decode returns the mask row by row (m[y*width+x])
+func (r RLE) Decode() []bool {
+	h, w := r.Size[0], r.Size[1]
+	m := make([]bool, h*w)
+	p := 0
+	for i, c := range r.Counts {
+		for j := 0; j < c && p < h*w; j++ {
+			if i%2 == 1 {
+				m[(p%h)*w+p/h] = true
+			}
+			p++
+		}
+	}
+	return m
+}
+
+//ParseRLE decodes the compressed COCO counts string of a mask of height x width pixels
+func ParseRLE(counts string, height, width int) (RLE, error) {
+	r := RLE{Size: [2]int{height, width}, Counts: []int{}}
+	p := 0
+	for p < len(counts) {
+		x := int64(0)
+		k := uint(0)
+		more := true
+		for more {
+			if p >= len(counts) {
+				return RLE{}, fmt.Errorf("truncated rle counts")
+			}
+			c := int64(counts[p]) - 48
+			if c < 0 || c > 63 {
+				return RLE{}, fmt.Errorf("invalid rle counts character %q", counts[p])
+			}
+			x |= (c & 0x1f) << (5 * k)
+			more = c&0x20 != 0
+			p++
+			k++
+			if !more && c&0x10 != 0 {
+				x |= -1 << (5 * k)
+			}
+		}
+		m := len(r.Counts)
+		if m > 2 {
+			x += int64(r.Counts[m-2])
+		}
+		if x < 0 {
+			return RLE{}, fmt.Errorf("invalid rle counts")
+		}
+		r.Counts = append(r.Counts, int(x))
+	}
+	return r, r.validate()
+}
+
+//String returns the compressed COCO counts string
+func (r RLE) String() string {
+	var sb strings.Builder
+	for i := range r.Counts {
+		x := int64(r.Counts[i])
+		if i > 2 {
+			x -= int64(r.Counts[i-2])
+		}
+		more := true
+		for more {
+			c := x & 0x1f
+			x >>= 5
+			if c&0x10 != 0 {
+				more = x != -1
+			} else {
+				more = x != 0
+			}
+			if more {
+				c |= 0x20
+			}
+			sb.WriteByte(byte(c + 48))
+		}
+	}
+	return sb.String()
+}
+
+//MarshalJSON encodes the mask as a COCO compressed RLE
+func (r RLE) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Size   [2]int `json:"size"`
+		Counts string `json:"counts"`
+	}{r.Size, r.String()})
+}
+
+//UnmarshalJSON decodes a COCO RLE with either compressed string or uncompressed list counts
+func (r *RLE) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Size   [2]int          `json:"size"`
+		Counts json.RawMessage `json:"counts"`
+	}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	var s string
+	if json.Unmarshal(v.Counts, &s) == nil {
+		*r, err = ParseRLE(s, v.Size[0], v.Size[1])
+		return err
+	}
+	rle := RLE{Size: v.Size}
+	err = json.Unmarshal(v.Counts, &rle.Counts)
+	if err != nil {
+		return fmt.Errorf("rle counts should be a string or a list of integers")
+	}
+	err = rle.validate()
+	if err != nil {
+		return err
+	}
+	*r = rle
+	return nil
+}
+
+func (r RLE) validate() error {
+	if r.Size[0] < 0 || r.Size[1] < 0 {
+		return fmt.Errorf("invalid rle size %v", r.Size)
+	}
+	n := 0
+	for _, c := range r.Counts {
+		if c < 0 {
+			return fmt.Errorf("rle counts must be >= 0")
+		}
+		n += c
+	}
+	if n != r.Size[0]*r.Size[1] {
+		return fmt.Errorf("rle counts cover %d pixels instead of %d", n, r.Size[0]*r.Size[1])
+	}
+	return nil
+}
+
+//Area is the number of pixels in the mask
+func (r RLE) Area() int {
+	a := 0
+	for i := 1; i < len(r.Counts); i += 2 {
+		a += r.Counts[i]
+	}
+	return a
+}
+
+//Translate moves the mask dx pixels right and dy pixels down. Pixels moved out of the image are dropped
+func (r RLE) Translate(dx, dy int) RLE {
+	if dx == 0 && dy == 0 {
+		return r
+	}
+	h, w := r.Size[0], r.Size[1]
+	m := r.Decode()
+	t := make([]bool, len(m))
+	for y := 0; y < h; y++ {
+		ty := y + dy
+		if ty < 0 || ty >= h {
+			continue
+		}
+		for x := 0; x < w; x++ {
+			tx := x + dx
+			if tx >= 0 && tx < w {
+				t[ty*w+tx] = m[y*w+x]
+			}
+		}
+	}
+	tr, _ := EncodeMask(t, h, w)
+	return tr
+}
+
+//MaskIOU computes the intersection over union of two masks. It is 0 for masks of different sizes
+func MaskIOU(a, b RLE) float64 {
+	if a.Size != b.Size {
+		return 0
+	}
+	union := a.Area() + b.Area()
+	if union == 0 {
+		return 0
+	}
+	inter := maskIntersection(a, b)
+	return float64(inter) / float64(union-inter)
+}
+
+//maskIntersection walks both run lists together and counts pixels set in both masks
+func maskIntersection(a, b RLE) int {
+	inter := 0
+	i, j := 0, 0
+	ra, rb := 0, 0
+	for {
+		for ra == 0 && i < len(a.Counts) {
+			ra = a.Counts[i]
+			i++
+		}
+		for rb == 0 && j < len(b.Counts) {
+			rb = b.Counts[j]
+			j++
+		}
+		if ra == 0 || rb == 0 {
+			return inter
+		}
+		n := ra
+		if rb < n {
+			n = rb
+		}
+		//the run read last from Counts[i-1] is set when i-1 is odd
+		if i%2 == 0 && j%2 == 0 {
+			inter += n
+		}
+		ra -= n
+		rb -= n
+	}
+}
+
+//predictedMask moves the tracker mask along with its box to the reference box ref
+func (k *KalmanBoxTracker) predictedMask(ref []float64) RLE {
+	b0, b1 := imageBox(k.MotionModel, k.LastBBox), imageBox(k.MotionModel, ref)
+	if b0 == nil || b1 == nil {
+		return *k.Mask
+	}
+	dx := (b1[0] + b1[2] - b0[0] - b0[2]) / 2
+	dy := (b1[1] + b1[3] - b0[1] - b0[3]) / 2
+	return k.Mask.Translate(int(math.Round(dx)), int(math.Round(dy)))
+}
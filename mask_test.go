@@ -0,0 +1,151 @@
+package sort
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+//rect returns a height x width mask with the pixels in [x1,x2) x [y1,y2) set
+func rect(height, width, x1, y1, x2, y2 int) RLE {
+	m := make([]bool, height*width)
+	for y := y1; y < y2; y++ {
+		for x := x1; x < x2; x++ {
+			if x >= 0 && x < width && y >= 0 && y < height {
+				m[y*width+x] = true
+			}
+		}
+	}
+	r, _ := EncodeMask(m, height, width)
+	return r
+}
+
+func TestRLE(t *testing.T) {
+	r := RLE{Size: [2]int{1, 3}, Counts: []int{1, 2}}
+	if r.String() != "12" {
+		t.Errorf("wrong compressed counts %q", r.String())
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	m := make([]bool, 30*50)
+	for i := range m {
+		m[i] = rnd.Float64() < 0.3 || i > 1000
+	}
+	r, err := EncodeMask(m, 30, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Decode(), m) {
+		t.Errorf("decoded mask differs")
+	}
+	p, err := ParseRLE(r.String(), 30, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, r) {
+		t.Errorf("parsed counts differ")
+	}
+	_, err = ParseRLE(r.String(), 30, 51)
+	if err == nil {
+		t.Errorf("counts not covering the mask should be rejected")
+	}
+
+	var d Detection
+	err = json.Unmarshal([]byte(`{"bbox":[0,0,2,1],"mask":{"size":[2,3],"counts":[1,2,3]}}`), &d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Mask == nil || d.Mask.Area() != 2 || !reflect.DeepEqual(d.Mask.Decode(), []bool{false, true, false, true, false, false}) {
+		t.Errorf("wrong uncompressed mask %+v", d.Mask)
+	}
+	b, _ := json.Marshal(r)
+	var u RLE
+	err = json.Unmarshal(b, &u)
+	if err != nil || !reflect.DeepEqual(u, r) {
+		t.Errorf("json round trip failed. err=%v", err)
+	}
+
+	a := rect(40, 60, 10, 5, 30, 25)
+	c := rect(40, 60, 20, 5, 40, 25)
+	if MaskIOU(a, c) != 200.0/600 {
+		t.Errorf("wrong mask IOU %f", MaskIOU(a, c))
+	}
+	if MaskIOU(a, a.Translate(10, 0)) != 200.0/600 || a.Translate(50, 0).Area() != 0 {
+		t.Errorf("wrong translation")
+	}
+	if MaskIOU(a, rect(40, 61, 10, 5, 30, 25)) != 0 {
+		t.Errorf("masks of different sizes should not overlap")
+	}
+}
+
+func TestMaskTracking(t *testing.T) {
+	s, err := NewSORT(WithCostFunction("mask-iou"), WithIOUThreshold(0.3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//two people in a crowd with almost the same boxes, jittering more than the distance between them
+	sides := map[int64]bool{}
+	for f := 0; f < 30; f++ {
+		x := 100 + 2*f
+		j := 6 * (f % 2)
+		dets := []Detection{
+			{BBox: []float64{float64(x + j), 100, float64(x + j + 100), 300}, Score: 0.9},
+			{BBox: []float64{float64(x + 6 - j), 100, float64(x + 106 - j), 300}, Score: 0.9},
+		}
+		left := rect(400, 600, x, 100, x+40, 300)
+		right := rect(400, 600, x+66, 100, x+106, 300)
+		dets[0].Mask = &left
+		dets[1].Mask = &right
+		if f%3 == 1 {
+			dets[0], dets[1] = dets[1], dets[0]
+		}
+		err = s.UpdateDetections(dets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tr := range s.Tracks() {
+			isLeft := MaskIOU(*tr.Mask, left) > 0
+			prev, ok := sides[tr.ID]
+			if ok && prev != isLeft {
+				t.Fatalf("track switched people at frame %d. id=%d", f, tr.ID)
+			}
+			sides[tr.ID] = isLeft
+		}
+	}
+	if len(sides) != 2 {
+		t.Errorf("expected 2 tracks, got %d", len(sides))
+	}
+
+	//detections without masks are matched by their boxes
+	ids := len(s.Trackers)
+	err = s.UpdateDetections([]Detection{{BBox: []float64{160, 100, 260, 300}, Score: 0.9}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Tracks()) != 1 || len(s.Trackers) != ids {
+		t.Errorf("detection without mask should match a tracker")
+	}
+}
+
+func TestMaskCopies(t *testing.T) {
+	s, err := NewSORT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mask := rect(100, 100, 10, 10, 50, 50)
+	emb := []float64{1, 0}
+	err = s.UpdateDetections([]Detection{{BBox: []float64{10, 10, 50, 50}, Score: 0.9, Mask: &mask, Embedding: emb}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	//callers may reuse their buffers, and tracks must not change the tracker state
+	mask.Counts[0] = 0
+	emb[0] = 0
+	tr := s.Tracks()[0]
+	tr.Mask.Counts[1] = 0
+	trk := s.Trackers[0]
+	if trk.Mask == &mask || trk.Mask.Counts[0] == 0 || trk.Mask.Counts[1] == 0 || trk.Embedding[0] != 1 {
+		t.Errorf("tracker should keep its own copies of masks and embeddings. mask=%v embedding=%v", trk.Mask.Counts[:2], trk.Embedding)
+	}
+}
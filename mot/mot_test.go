@@ -0,0 +1,81 @@
+package mot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/flaviostutz/sort"
+)
+
+func TestRead(t *testing.T) {
+	det := `1,-1,10,20,30,40,0.9,-1,-1,-1
+1,-1,100,100,20,20,0.2,-1,-1,-1
+
+3,-1,12,22,30,40,0.8,-1,-1,-1
+`
+	rows, err := Read(strings.NewReader(det))
+	if err != nil {
+		t.Fatalf("Error reading detections. err=%s", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, found %d", len(rows))
+	}
+	if rows[0].BBox[2] != 40 || rows[0].BBox[3] != 60 || rows[0].Class != -1 {
+		t.Errorf("Unexpected row %+v", rows[0])
+	}
+
+	frames := GroupByFrame(rows)
+	if len(frames) != 3 || len(frames[1]) != 0 || len(frames[2]) != 1 {
+		t.Errorf("Unexpected frames %v", frames)
+	}
+	dets := Detections(frames[0], 0.5)
+	if len(dets) != 1 || dets[0][4] != 0.9 {
+		t.Errorf("Unexpected detections %v", dets)
+	}
+
+	gt, err := ParseRow("5, 3, 1, 2, 3, 4, 0, 7, 0.25")
+	if err != nil {
+		t.Fatalf("Error parsing gt row. err=%s", err)
+	}
+	if gt.Frame != 5 || gt.ID != 3 || gt.Conf != 0 || gt.Class != 7 || gt.Visibility != 0.25 {
+		t.Errorf("Unexpected gt row %+v", gt)
+	}
+
+	_, err = ParseRow("1,2,3")
+	if err == nil {
+		t.Errorf("Short rows should be rejected")
+	}
+}
+
+func TestWriteTracks(t *testing.T) {
+	s, err := sort.NewSORT()
+	if err != nil {
+		t.Fatalf("Error creating SORT. err=%s", err)
+	}
+	err = s.Update([][]float64{{10, 20, 40, 60, 0.9}})
+	if err != nil {
+		t.Fatalf("Error updating SORT. err=%s", err)
+	}
+
+	buf := bytes.Buffer{}
+	w := NewWriter(&buf)
+	tracks := s.Tracks()
+	err = w.WriteTracks(1, tracks)
+	if err != nil {
+		t.Fatalf("Error writing tracks. err=%s", err)
+	}
+	w.Flush()
+
+	rows, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Error reading written tracks. err=%s", err)
+	}
+	if len(rows) != 1 || rows[0].ID != tracks[0].ID || rows[0].ID < 1 || rows[0].BBox[2] != 40 {
+		t.Errorf("Unexpected written rows %+v", rows)
+	}
+	err = w.WriteRow(Row{Frame: 1, ID: 1, BBox: []float64{1, 2, 3, 4, 5, 6, 0}})
+	if err == nil {
+		t.Errorf("3D boxes should be rejected")
+	}
+}
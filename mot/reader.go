@@ -0,0 +1,141 @@
+//Package mot reads and writes files in the MOTChallenge (MOT16/MOT17/MOT20) text format
+package mot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//Row is one line of a MOTChallenge det.txt, gt.txt or results file
+type Row struct {
+	Frame int
+	//ID is the object ID. It is -1 in detection files
+	ID int64
+	//BBox is in the form [x1,y1,x2,y2]
+	BBox []float64
+	//Conf is the detection confidence. In gt.txt it is 0 for entries that must be ignored
+	Conf float64
+	//Class is the object class in gt.txt (1 is pedestrian). -1 when not present
+	Class int
+	//Visibility is the visible ratio of the object in gt.txt. -1 when not present
+	Visibility float64
+}
+
+//ReadFile parses a MOTChallenge file
+func ReadFile(file string) ([]Row, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+//Read parses MOTChallenge lines in the form frame,id,bb_left,bb_top,w,h,conf[,class,visibility|,x,y,z].
+//Fields may be separated by commas and/or spaces. Empty lines are ignored
+func Read(r io.Reader) ([]Row, error) {
+	rows := make([]Row, 0)
+	scanner := bufio.NewScanner(r)
+	ln := 0
+	for scanner.Scan() {
+		ln = ln + 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		row, err := ParseRow(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", ln, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+//ParseRow parses a single MOTChallenge line
+func ParseRow(line string) (Row, error) {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) < 6 {
+		return Row{}, fmt.Errorf("expected at least 6 fields, found %d", len(fields))
+	}
+	v := make([]float64, len(fields))
+	for i, f := range fields {
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return Row{}, fmt.Errorf("invalid field %d %q", i+1, f)
+		}
+		v[i] = n
+	}
+	row := Row{
+		Frame:      int(v[0]),
+		ID:         int64(v[1]),
+		BBox:       []float64{v[2], v[3], v[2] + v[4], v[3] + v[5]},
+		Conf:       1,
+		Class:      -1,
+		Visibility: -1,
+	}
+	if len(v) > 6 {
+		row.Conf = v[6]
+	}
+	//gt.txt has 9 columns. det.txt and results have 10, with -1,-1,-1 world coordinates
+	if len(v) == 9 {
+		row.Class = int(v[7])
+		row.Visibility = v[8]
+	}
+	return row, nil
+}
+
+//GroupByFrame groups rows by frame. Element i holds the rows of frame i+1 so that frames
+//without rows are still present, as SORT.Update must be called once for each frame
+func GroupByFrame(rows []Row) [][]Row {
+	last := 0
+	for _, r := range rows {
+		if r.Frame > last {
+			last = r.Frame
+		}
+	}
+	frames := make([][]Row, last)
+	for i := range frames {
+		frames[i] = make([]Row, 0)
+	}
+	for _, r := range rows {
+		if r.Frame < 1 {
+			continue
+		}
+		frames[r.Frame-1] = append(frames[r.Frame-1], r)
+	}
+	return frames
+}
+
+//Detections converts rows with Conf >= minConf to the [x1,y1,x2,y2,score] format used by SORT.Update
+func Detections(rows []Row, minConf float64) [][]float64 {
+	dets := make([][]float64, 0)
+	for _, r := range rows {
+		if r.Conf < minConf {
+			continue
+		}
+		dets = append(dets, []float64{r.BBox[0], r.BBox[1], r.BBox[2], r.BBox[3], r.Conf})
+	}
+	return dets
+}
+
+//ReadDetections reads a det.txt file and returns per frame detections ready for SORT.Update.
+//Element i holds the detections of frame i+1
+func ReadDetections(file string, minConf float64) ([][][]float64, error) {
+	rows, err := ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	frames := GroupByFrame(rows)
+	dets := make([][][]float64, len(frames))
+	for i, f := range frames {
+		dets[i] = Detections(f, minConf)
+	}
+	return dets, nil
+}
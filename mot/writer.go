@@ -0,0 +1,46 @@
+package mot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/flaviostutz/sort"
+)
+
+//Writer writes tracker results in the MOTChallenge submission format
+type Writer struct {
+	w *bufio.Writer
+}
+
+//NewWriter creates a results writer
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+//WriteRow writes a single line in the form frame,id,bb_left,bb_top,w,h,conf,-1,-1,-1
+func (m *Writer) WriteRow(r Row) error {
+	if len(r.BBox) != 4 {
+		return fmt.Errorf("bbox should contain 4 positions: x1,y1,x2,y2")
+	}
+	_, err := fmt.Fprintf(m.w, "%d,%d,%.2f,%.2f,%.2f,%.2f,%.2f,-1,-1,-1\n",
+		r.Frame, r.ID, r.BBox[0], r.BBox[1], r.BBox[2]-r.BBox[0], r.BBox[3]-r.BBox[1], r.Conf)
+	return err
+}
+
+//WriteTracks writes the tracks of a frame. Tracker IDs are already 1-based as required by the benchmark.
+//Confidence is always written as 1, as sort.py does
+func (m *Writer) WriteTracks(frame int, tracks []sort.Track) error {
+	for _, t := range tracks {
+		err := m.WriteRow(Row{Frame: frame, ID: t.ID, BBox: t.BBox, Conf: 1})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Flush writes buffered data to the underlying writer
+func (m *Writer) Flush() error {
+	return m.w.Flush()
+}
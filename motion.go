@@ -0,0 +1,188 @@
+package sort
+
+import (
+	"fmt"
+
+	"github.com/flaviostutz/kalman"
+	"github.com/konimarti/lti"
+	"gonum.org/v1/gonum/mat"
+)
+
+//MotionModel describes the Kalman filter used by a tracker and how boxes are mapped to and from its state
+type MotionModel interface {
+	//Name identifies the model in configurations
+	Name() string
+	//System returns the discrete linear system, its noise and the initial state covariance
+	System() (lti.Discrete, kalman.Noise, *mat.Dense)
+	//ToMeasurement converts a bounding box to the measurement vector z
+	ToMeasurement(bbox []float64) []float64
+	//ToBox converts a state vector back to a bounding box
+	ToBox(x mat.Vector) []float64
+	//Constrain fixes predicted states that fell out of the model domain
+	Constrain(x *mat.VecDense)
+}
+
+//VelocityModel is implemented by motion models whose state has velocities
+type VelocityModel interface {
+	//Velocity returns the box center velocity in pixels per frame
+	Velocity(x mat.Vector) []float64
+}
+
+//ConstantVelocity is the original SORT model. State is [x,y,s,r,vx,vy,vs] where x,y is the box center,
+//s is the area and r is the aspect ratio (kept constant)
+type ConstantVelocity struct {
+	//ProcessNoise scales the process noise covariance Q. 1 keeps the original SORT values
+	ProcessNoise float64
+}
+
+//Name identifies the model in configurations
+func (m ConstantVelocity) Name() string {
+	return "constant-velocity"
+}
+
+//System returns the discrete linear system, its noise and the initial state covariance
+func (m ConstantVelocity) System() (lti.Discrete, kalman.Noise, *mat.Dense) {
+	q := m.ProcessNoise
+	sys := lti.Discrete{
+		Ad: mat.NewDense(7, 7, []float64{
+			1, 0, 0, 0, 1, 0, 0,
+			0, 1, 0, 0, 0, 1, 0,
+			0, 0, 1, 0, 0, 0, 1,
+			0, 0, 0, 1, 0, 0, 0,
+			0, 0, 0, 0, 1, 0, 0,
+			0, 0, 0, 0, 0, 1, 0,
+			0, 0, 0, 0, 0, 0, 1}),
+		Bd: mat.NewDense(7, 7, nil),
+		C: mat.NewDense(4, 7, []float64{
+			1, 0, 0, 0, 0, 0, 0,
+			0, 1, 0, 0, 0, 0, 0,
+			0, 0, 1, 0, 0, 0, 0,
+			0, 0, 0, 1, 0, 0, 0}),
+		D: mat.NewDense(4, 7, nil),
+	}
+	nse := kalman.Noise{
+		Q: mat.NewDense(7, 7, []float64{
+			q, 0, 0, 0, 0, 0, 0,
+			0, q, 0, 0, 0, 0, 0,
+			0, 0, q, 0, 0, 0, 0,
+			0, 0, 0, q, 0, 0, 0,
+			0, 0, 0, 0, 0.01 * q, 0, 0,
+			0, 0, 0, 0, 0, 0.01 * q, 0,
+			0, 0, 0, 0, 0, 0, 0.0001 * q}),
+		R: mat.NewDense(4, 4, []float64{
+			1, 0, 0, 0,
+			0, 1, 0, 0,
+			0, 0, 10, 0,
+			0, 0, 0, 10}),
+	}
+	p := mat.NewDense(7, 7, []float64{
+		10, 0, 0, 0, 1, 0, 0,
+		0, 10, 0, 0, 0, 1, 0,
+		0, 0, 10, 0, 0, 0, 1,
+		0, 0, 0, 10, 0, 0, 0,
+		0, 0, 0, 0, 1000, 0, 0,
+		0, 0, 0, 0, 0, 10, 0,
+		0, 0, 0, 0, 0, 0, 10})
+	return sys, nse, p
+}
+
+//ToMeasurement converts a bounding box to the measurement vector z
+func (m ConstantVelocity) ToMeasurement(bbox []float64) []float64 {
+	return convertBBoxToZ(bbox)
+}
+
+//ToBox converts a state vector back to a bounding box
+func (m ConstantVelocity) ToBox(x mat.Vector) []float64 {
+	return convertZToBBox([]float64{x.AtVec(0), x.AtVec(1), x.AtVec(2), x.AtVec(3)})
+}
+
+//Velocity returns the box center velocity in pixels per frame
+func (m ConstantVelocity) Velocity(x mat.Vector) []float64 {
+	return []float64{x.AtVec(4), x.AtVec(5)}
+}
+
+//Constrain avoids predicting negative areas
+func (m ConstantVelocity) Constrain(x *mat.VecDense) {
+	if x.AtVec(6)+x.AtVec(2) <= 0 {
+		x.SetVec(6, 0.0)
+	}
+}
+
+//ConstantPosition models objects that barely move (random walk). State is [x,y,s,r]
+type ConstantPosition struct {
+	//ProcessNoise scales the process noise covariance Q
+	ProcessNoise float64
+}
+
+//Name identifies the model in configurations
+func (m ConstantPosition) Name() string {
+	return "constant-position"
+}
+
+//System returns the discrete linear system, its noise and the initial state covariance
+func (m ConstantPosition) System() (lti.Discrete, kalman.Noise, *mat.Dense) {
+	q := m.ProcessNoise
+	sys := lti.Discrete{
+		Ad: identity(4),
+		Bd: mat.NewDense(4, 4, nil),
+		C:  identity(4),
+		D:  mat.NewDense(4, 4, nil),
+	}
+	nse := kalman.Noise{
+		Q: mat.NewDense(4, 4, []float64{
+			q, 0, 0, 0,
+			0, q, 0, 0,
+			0, 0, q, 0,
+			0, 0, 0, 0.01 * q}),
+		R: mat.NewDense(4, 4, []float64{
+			1, 0, 0, 0,
+			0, 1, 0, 0,
+			0, 0, 10, 0,
+			0, 0, 0, 10}),
+	}
+	p := mat.NewDense(4, 4, []float64{
+		10, 0, 0, 0,
+		0, 10, 0, 0,
+		0, 0, 10, 0,
+		0, 0, 0, 10})
+	return sys, nse, p
+}
+
+//ToMeasurement converts a bounding box to the measurement vector z
+func (m ConstantPosition) ToMeasurement(bbox []float64) []float64 {
+	return convertBBoxToZ(bbox)
+}
+
+//ToBox converts a state vector back to a bounding box
+func (m ConstantPosition) ToBox(x mat.Vector) []float64 {
+	return convertZToBBox([]float64{x.AtVec(0), x.AtVec(1), x.AtVec(2), x.AtVec(3)})
+}
+
+//Constrain does nothing as this model never changes the area by itself
+func (m ConstantPosition) Constrain(x *mat.VecDense) {
+}
+
+//NewMotionModel returns the motion model registered with name
+func NewMotionModel(name string, processNoise float64) (MotionModel, error) {
+	switch name {
+	case "constant-velocity":
+		return ConstantVelocity{ProcessNoise: processNoise}, nil
+	case "constant-position":
+		return ConstantPosition{ProcessNoise: processNoise}, nil
+	case "constant-velocity-3d":
+		return ConstantVelocity3D{ProcessNoise: processNoise}, nil
+	case "constant-velocity-rotated":
+		return ConstantVelocityRotated{ProcessNoise: processNoise}, nil
+	case "constant-velocity-point":
+		return ConstantVelocityPoint{ProcessNoise: processNoise}, nil
+	}
+	return nil, fmt.Errorf("unknown motion model %q", name)
+}
+
+func identity(n int) *mat.Dense {
+	d := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		d.Set(i, i, 1)
+	}
+	return d
+}
@@ -0,0 +1,284 @@
+//Package multicam tracks objects across overlapping cameras. Each camera has its own SORT session and a homography
+//to a shared ground plane. Tracks are located on the ground plane by their bottom center and tracks of different
+//cameras at the same place get the same global ID, which is kept as objects move from one view to another
+package multicam
+
+import (
+	"fmt"
+	"math"
+	gosort "sort"
+
+	"github.com/cpmech/gosl/graph"
+	"github.com/flaviostutz/sort"
+	"github.com/sirupsen/logrus"
+)
+
+//Camera is a view of the shared ground plane
+type Camera struct {
+	Name string
+	//Homography maps image pixels of this camera to ground plane coordinates (usually meters)
+	Homography sort.Homography
+	//Options create the SORT session of this camera
+	Options []sort.Option
+}
+
+//Config holds the cross camera association parameters
+type Config struct {
+	//MaxDistance is the largest ground plane distance between tracks of different cameras that are the same object
+	MaxDistance float64
+	//MaxAge is the number of frames a global ID is kept after its last observation in any camera
+	MaxAge int
+}
+
+//DefaultConfig returns parameters for people tracked on a ground plane in meters
+func DefaultConfig() Config {
+	return Config{MaxDistance: 1, MaxAge: 30}
+}
+
+//Validate checks that parameters are in their valid ranges
+func (c Config) Validate() error {
+	if c.MaxDistance <= 0 {
+		return fmt.Errorf("maxDistance must be > 0")
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("maxAge must be >= 0")
+	}
+	return nil
+}
+
+//Track is a track of a camera with its global ID
+type Track struct {
+	sort.Track
+	Camera   string `json:"camera"`
+	GlobalID int64  `json:"globalId"`
+	//World is the bottom center of the box on the ground plane
+	World [2]float64 `json:"world"`
+}
+
+//Tracker keeps one SORT session per camera and assigns global IDs to their tracks
+type Tracker struct {
+	config   Config
+	cameras  map[string]*camera
+	globals  map[int64]*global
+	lastID   int64
+	maxFrame int
+}
+
+type camera struct {
+	Camera
+	sort *sort.SORT
+	//global IDs of the local trackers
+	ids map[int64]int64
+}
+
+//global is an object seen by one or more cameras
+type global struct {
+	id  int64
+	obs map[string]observation
+}
+
+type observation struct {
+	world [2]float64
+	frame int
+}
+
+//NewTracker creates a tracker for cameras
+func NewTracker(c Config, cameras ...Camera) (*Tracker, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(cameras) == 0 {
+		return nil, fmt.Errorf("at least one camera is needed")
+	}
+	t := &Tracker{
+		config:  c,
+		cameras: make(map[string]*camera),
+		globals: make(map[int64]*global),
+	}
+	for _, cam := range cameras {
+		if cam.Name == "" {
+			return nil, fmt.Errorf("camera name must not be empty")
+		}
+		_, ok := t.cameras[cam.Name]
+		if ok {
+			return nil, fmt.Errorf("duplicate camera %q", cam.Name)
+		}
+		s, err := sort.NewSORT(cam.Options...)
+		if err != nil {
+			return nil, fmt.Errorf("camera %q. err=%s", cam.Name, err)
+		}
+		//tracks are located by the bottom center of their image box
+		if s.ImageBox(make([]float64, s.BoxSize())) == nil {
+			return nil, fmt.Errorf("camera %q. motion model has no image boxes", cam.Name)
+		}
+		t.cameras[cam.Name] = &camera{Camera: cam, sort: s, ids: make(map[int64]int64)}
+	}
+	return t, nil
+}
+
+//Session returns the SORT session of a camera
+func (t *Tracker) Session(name string) *sort.SORT {
+	c, ok := t.cameras[name]
+	if !ok {
+		return nil
+	}
+	return c.sort
+}
+
+//Update tracks the detections of a camera frame and returns its tracks with global IDs.
+//Frame numbers must come from a clock shared by all cameras. Cameras may be updated in any order
+func (t *Tracker) Update(name string, frame int, dets []sort.Detection) ([]Track, error) {
+	c, ok := t.cameras[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown camera %q", name)
+	}
+	err := c.sort.UpdateDetections(dets)
+	if err != nil {
+		return nil, err
+	}
+	if frame > t.maxFrame {
+		t.maxFrame = frame
+	}
+
+	//forget local trackers removed by the session
+	alive := make(map[int64]bool)
+	for _, trk := range c.sort.Trackers {
+		alive[trk.ID] = true
+	}
+	//their last observations are kept, so the object can be picked up again by any camera during MaxAge frames
+	for id := range c.ids {
+		if !alive[id] {
+			delete(c.ids, id)
+		}
+	}
+	t.expire()
+
+	tracks := c.sort.Tracks()
+	result := make([]Track, len(tracks))
+	unassigned := make([]int, 0)
+	for i, tr := range tracks {
+		result[i] = Track{Track: tr, Camera: name, World: c.world(c.sort.ImageBox(tr.BBox))}
+		gid, ok := c.ids[tr.ID]
+		if ok {
+			result[i].GlobalID = gid
+		} else {
+			unassigned = append(unassigned, i)
+		}
+	}
+	t.assign(c, frame, result, unassigned)
+
+	for _, r := range result {
+		c.ids[r.ID] = r.GlobalID
+		t.globals[r.GlobalID].obs[name] = observation{world: r.World, frame: frame}
+	}
+	return result, nil
+}
+
+//assign links the tracks that have no global ID yet to globals seen by other cameras, or creates new ones
+func (t *Tracker) assign(c *camera, frame int, tracks []Track, unassigned []int) {
+	if len(unassigned) == 0 {
+		return
+	}
+	//globals already followed by a tracker of this camera can't get another one
+	taken := make(map[int64]bool)
+	for _, gid := range c.ids {
+		taken[gid] = true
+	}
+	candidates := make([]*global, 0)
+	for _, g := range t.globals {
+		if !taken[g.id] && len(g.obs) > 0 {
+			candidates = append(candidates, g)
+		}
+	}
+	gosort.Slice(candidates, func(i, j int) bool { return candidates[i].id < candidates[j].id })
+
+	if len(candidates) > 0 {
+		cost := make([][]float64, len(unassigned))
+		for i, ti := range unassigned {
+			cost[i] = make([]float64, len(candidates))
+			for j, g := range candidates {
+				//all pairs too far apart cost the same, so that they don't influence the other pairs
+				d := g.distance(tracks[ti].World)
+				if math.IsNaN(d) || d > t.config.MaxDistance {
+					d = 2 * t.config.MaxDistance
+				}
+				cost[i][j] = d
+			}
+		}
+		mk := graph.Munkres{}
+		mk.Init(len(cost), len(candidates))
+		mk.SetCostMatrix(cost)
+		mk.Run()
+		for i, j := range mk.Links {
+			if j == -1 || cost[i][j] > t.config.MaxDistance {
+				continue
+			}
+			tracks[unassigned[i]].GlobalID = candidates[j].id
+			logrus.Debugf("Track handed over. camera=%s id=%d globalId=%d distance=%f", c.Name, tracks[unassigned[i]].ID, candidates[j].id, cost[i][j])
+		}
+	}
+
+	for _, ti := range unassigned {
+		if tracks[ti].GlobalID != 0 {
+			continue
+		}
+		t.lastID++
+		t.globals[t.lastID] = &global{id: t.lastID, obs: make(map[string]observation)}
+		tracks[ti].GlobalID = t.lastID
+		logrus.Debugf("New global track. camera=%s id=%d globalId=%d", c.Name, tracks[ti].ID, t.lastID)
+	}
+}
+
+//expire removes observations older than MaxAge and globals without observations
+func (t *Tracker) expire() {
+	for gid, g := range t.globals {
+		for cam, o := range g.obs {
+			if t.maxFrame-o.frame > t.config.MaxAge {
+				delete(g.obs, cam)
+			}
+		}
+		if len(g.obs) > 0 {
+			continue
+		}
+		followed := false
+		for _, c := range t.cameras {
+			for _, id := range c.ids {
+				if id == gid {
+					followed = true
+				}
+			}
+		}
+		if !followed {
+			delete(t.globals, gid)
+		}
+	}
+}
+
+//Mapping returns the global ID of each local tracker, by camera
+func (t *Tracker) Mapping() map[string]map[int64]int64 {
+	r := make(map[string]map[int64]int64)
+	for name, c := range t.cameras {
+		m := make(map[int64]int64)
+		for id, gid := range c.ids {
+			m[id] = gid
+		}
+		r[name] = m
+	}
+	return r
+}
+
+//world projects the bottom center of an [x1,y1,x2,y2] image box to the ground plane
+func (c *camera) world(bbox []float64) [2]float64 {
+	x, y := c.Homography.Project((bbox[0]+bbox[2])/2, bbox[3])
+	return [2]float64{x, y}
+}
+
+//distance is the ground plane distance to the closest recent observation of g
+func (g *global) distance(p [2]float64) float64 {
+	d := math.Inf(1)
+	for _, o := range g.obs {
+		d = math.Min(d, math.Hypot(o.world[0]-p[0], o.world[1]-p[1]))
+	}
+	return d
+}
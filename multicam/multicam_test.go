@@ -0,0 +1,110 @@
+package multicam
+
+import (
+	"testing"
+
+	"github.com/flaviostutz/sort"
+)
+
+//person returns the detection of a person standing at wx,wy meters in a camera that sees
+//the ground plane from above at 100 pixels per meter, with its origin at ox meters
+func person(wx, wy, ox float64) []sort.Detection {
+	px, py := (wx-ox)*100, wy*100
+	if px-20 < 0 || px+20 > 640 {
+		return []sort.Detection{}
+	}
+	return []sort.Detection{{BBox: []float64{px - 20, py - 100, px + 20, py}, Score: 0.9}}
+}
+
+func TestHandoff(t *testing.T) {
+	opts := []sort.Option{sort.WithPreset("pedestrian"), sort.WithCostFunction("giou")}
+	a := Camera{Name: "a", Homography: sort.Homography{0.01, 0, 0, 0, 0.01, 0, 0, 0, 1}, Options: opts}
+	b := Camera{Name: "b", Homography: sort.Homography{0.01, 0, 5, 0, 0.01, 0, 0, 0, 1}, Options: opts}
+	tr, err := NewTracker(DefaultConfig(), a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walker := map[int64]bool{}
+	others := map[int64]bool{}
+	seen := map[string]bool{}
+	for f := 1; f <= 90; f++ {
+		wx := 1 + 0.1*float64(f)
+		da := append(person(wx, 2, 0), person(3, 4.5, 0)...)
+		db := append(person(wx, 2, 5), person(8, 4.5, 5)...)
+		for _, cam := range []struct {
+			name string
+			dets []sort.Detection
+		}{{"a", da}, {"b", db}} {
+			tracks, err := tr.Update(cam.name, f, cam.dets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, trk := range tracks {
+				if trk.World[1] < 3 {
+					walker[trk.GlobalID] = true
+					seen[cam.name] = true
+					if trk.World[0] < wx-0.5 || trk.World[0] > wx+0.5 {
+						t.Errorf("wrong world position. frame=%d camera=%s world=%v", f, cam.name, trk.World)
+					}
+				} else {
+					others[trk.GlobalID] = true
+				}
+			}
+		}
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("walker should be tracked in both cameras")
+	}
+	if len(walker) != 1 {
+		t.Errorf("walker should keep one global ID across cameras. ids=%v", walker)
+	}
+	if len(others) != 2 {
+		t.Errorf("static people in different places should get different IDs. ids=%v", others)
+	}
+	for id := range walker {
+		if others[id] {
+			t.Errorf("walker shares a global ID with another person")
+		}
+	}
+	m := tr.Mapping()
+	if len(m["b"]) != 2 {
+		t.Errorf("unexpected mapping %v", m)
+	}
+
+	_, err = tr.Update("c", 91, nil)
+	if err == nil {
+		t.Errorf("unknown camera should be rejected")
+	}
+	_, err = NewTracker(DefaultConfig(), a, a)
+	if err == nil {
+		t.Errorf("duplicate camera should be rejected")
+	}
+	_, err = NewTracker(Config{MaxDistance: 0}, a)
+	if err == nil {
+		t.Errorf("invalid config should be rejected")
+	}
+}
+
+func TestPointCameras(t *testing.T) {
+	h := sort.Homography{0.01, 0, 0, 0, 0.01, 0, 0, 0, 1}
+	tr, err := NewTracker(DefaultConfig(), Camera{Name: "radar", Homography: h, Options: []sort.Option{sort.WithPreset("point"), sort.WithMinUpdatesUsePrediction(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tracks []Track
+	for f := 1; f <= 3; f++ {
+		tracks, err = tr.Update("radar", f, []sort.Detection{{BBox: []float64{100 + float64(f), 200, 10}, Score: 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	//bottom of the point circle
+	if len(tracks) != 1 || tracks[0].World[0] < 0.9 || tracks[0].World[0] > 1.2 || tracks[0].World[1] < 2.05 || tracks[0].World[1] > 2.15 {
+		t.Errorf("point should be located by its enclosing box. tracks=%+v", tracks)
+	}
+
+	_, err = NewTracker(DefaultConfig(), Camera{Name: "lidar", Homography: h, Options: []sort.Option{sort.WithMotionModel("constant-velocity-3d"), sort.WithCostFunction("iou-3d")}})
+	if err == nil {
+		t.Errorf("3d boxes have no bottom center on the image and should be rejected")
+	}
+}
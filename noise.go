@@ -0,0 +1,53 @@
+package sort
+
+import (
+	"fmt"
+	"math"
+)
+
+//ScoreNoise maps the score of a detection to the factor applied to the measurement noise R of the motion model
+//when the detection updates a tracker, so that uncertain detections pull the state less
+type ScoreNoise func(score float64) float64
+
+//the measurement noise is scaled at most by these factors
+const (
+	minNoiseScale = 0.01
+	maxNoiseScale = 100
+)
+
+//NewScoreNoise returns the mapping registered with name. "nsa" scales R by 1-score as the NSA Kalman filter of
+//StrongSORT does and "inverse" by 1/score
+func NewScoreNoise(name string) (ScoreNoise, error) {
+	switch name {
+	case "nsa":
+		return func(score float64) float64 {
+			return 1 - score
+		}, nil
+	case "inverse":
+		return func(score float64) float64 {
+			return 1 / score
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown score noise %q", name)
+}
+
+//noiseScale is the factor of the measurement noise for a detection with n box values. Detections without
+//a score are not scaled. Scores <= 0 are taken as unknown too, as Detection.Score is 0 when Go callers don't set it
+func noiseScale(f ScoreNoise, bbox []float64, n int) float64 {
+	if f == nil || len(bbox) <= n || bbox[n] <= 0 {
+		return 1
+	}
+	s := f(clamp01(bbox[n]))
+	if math.IsNaN(s) {
+		return 1
+	}
+	return math.Max(minNoiseScale, math.Min(maxNoiseScale, s))
+}
+
+//scaleNoise sets the measurement noise used by the next update to the motion model noise times scale
+func (k *KalmanBoxTracker) scaleNoise(scale float64) {
+	if k.measurementNoise == nil {
+		return
+	}
+	k.measurementNoise.Scale(scale, k.baseNoise)
+}
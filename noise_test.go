@@ -0,0 +1,91 @@
+package sort
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestScoreNoise(t *testing.T) {
+	f, err := NewScoreNoise("nsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(noiseScale(f, []float64{0, 0, 10, 10, 0.8}, 4)-0.2) > 1e-9 {
+		t.Errorf("nsa should scale by 1-score")
+	}
+	if noiseScale(f, []float64{0, 0, 10, 10, 1}, 4) != minNoiseScale || noiseScale(f, []float64{0, 0, 10, 10}, 4) != 1 {
+		t.Errorf("wrong scale limits")
+	}
+	inv, err := NewScoreNoise("inverse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if noiseScale(inv, []float64{0, 0, 10, 10, 0}, 4) != 1 || noiseScale(inv, []float64{0, 0, 10, 10, -0.5}, 4) != 1 {
+		t.Errorf("scores <= 0 should be taken as unknown")
+	}
+	_, err = NewSORT(WithScoreNoise("unknown"))
+	if err == nil {
+		t.Errorf("unknown score noise should be rejected")
+	}
+
+	//a still object detected with confident boxes and uncertain boxes shifted by 20 pixels
+	track := func(opts ...Option) (float64, float64) {
+		s, err := NewSORT(append(opts, WithIOUThreshold(0.1), WithHistory())...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := 0.0
+		for f := 0; f < 40; f++ {
+			det := []float64{100, 100, 200, 300, 0.95}
+			if f%2 == 1 {
+				det = []float64{120, 100, 220, 300, 0.3}
+			}
+			err = s.Update([][]float64{det})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f >= 10 {
+				e += math.Abs(s.Trackers[0].CurrentState()[0] - 100)
+			}
+		}
+		if len(s.Trackers) != 1 {
+			t.Fatalf("expected 1 tracker, got %d", len(s.Trackers))
+		}
+		eh := 0.0
+		for _, est := range s.Trackers[0].History.Filtered()[10:] {
+			eh += math.Abs(est.BBox[0] - 100)
+		}
+		return e / 30, eh / 30
+	}
+	plain, plainHistory := track()
+	nsa, nsaHistory := track(WithScoreNoise("nsa"))
+	if nsa > 0.75*plain {
+		t.Errorf("uncertain detections should pull the state less. nsa error=%f plain error=%f", nsa, plain)
+	}
+	//the history records the tracker filter, so it uses the scaled noise too
+	if nsaHistory > 0.75*plainHistory {
+		t.Errorf("history should scale the noise. nsa error=%f plain error=%f", nsaHistory, plainHistory)
+	}
+	custom, _ := track(WithScoreNoiseFunc(func(score float64) float64 { return math.Pow(1-score, 2) }))
+	if custom == nsa {
+		t.Errorf("custom mapping should be used")
+	}
+
+	//detections without scores use the motion model noise
+	s, err := NewSORT(WithScoreNoise("nsa"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := 0; f < 3; f++ {
+		err = s.Update([][]float64{{100, 100, 200, 300}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	trk := s.Trackers[0]
+	if !mat.Equal(trk.measurementNoise, trk.baseNoise) {
+		t.Errorf("detections without score should not scale the noise")
+	}
+}
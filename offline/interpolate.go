@@ -0,0 +1,183 @@
+package offline
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/flaviostutz/sort"
+)
+
+//Interpolate fills gaps of up to maxGap missing frames inside each track, as the linear and GSI
+//post processors used with ByteTrack do. Created tracks have Interpolated set.
+//method is "linear", or "kalman" to follow the velocities estimated by a constant velocity Kalman
+//filter run forward and backward over the track, which gives smooth paths through the gap.
+//Boxes with other than 4 values, the points, oriented boxes and 3D boxes of the sort motion models, are
+//interpolated value by value, with angles turning the shortest way
+func Interpolate(frames []Frame, maxGap int, method string) ([]Frame, error) {
+	if maxGap < 0 {
+		return nil, fmt.Errorf("maxGap must be >= 0")
+	}
+	if method != "linear" && method != "kalman" {
+		return nil, fmt.Errorf("unknown interpolation method %q", method)
+	}
+
+	filled := make([]Tracklet, 0)
+	for _, tl := range Tracklets(frames) {
+		zs := centers(tl)
+		var fwd, bwd [][]float64
+		if method == "kalman" {
+			fwd, bwd = trackVelocities(tl, zs)
+		}
+		gaps := Tracklet{ID: tl.ID}
+		for i := 1; i < len(tl.Frames); i++ {
+			a, b := tl.Frames[i-1], tl.Frames[i]
+			missing := b - a - 1
+			if missing < 1 || missing > maxGap {
+				continue
+			}
+			ta, tb := tl.Tracks[i-1], tl.Tracks[i]
+			za, zb := zs[i-1], zs[i]
+			for f := a + 1; f < b; f++ {
+				s := float64(f-a) / float64(b-a)
+				z := make([]float64, len(za))
+				for k := range z {
+					if method == "kalman" {
+						z[k] = hermite(za[k], fwd[i-1][k], zb[k], bwd[i][k], float64(b-a), s)
+					} else {
+						z[k] = za[k] + s*(zb[k]-za[k])
+					}
+				}
+				gaps.Frames = append(gaps.Frames, f)
+				gaps.Tracks = append(gaps.Tracks, sort.Track{
+					ID:           tl.ID,
+					BBox:         centerToBox(z),
+					Score:        ta.Score + s*(tb.Score-ta.Score),
+					Class:        ta.Class,
+					Interpolated: true,
+				})
+			}
+		}
+		if len(gaps.Frames) > 0 {
+			filled = append(filled, gaps)
+		}
+	}
+	return Merge(frames, filled), nil
+}
+
+//hermite evaluates the cubic that goes from p0 with velocity v0 to p1 with velocity v1 in dt frames, at s in [0,1]
+func hermite(p0, v0, p1, v1, dt, s float64) float64 {
+	s2 := s * s
+	s3 := s2 * s
+	return (2*s3-3*s2+1)*p0 + (s3-2*s2+s)*dt*v0 + (-2*s3+3*s2)*p1 + (s3-s2)*dt*v1
+}
+
+//centers returns the boxes of tl in the form they are interpolated, with angles unwrapped along the tracklet
+func centers(tl Tracklet) [][]float64 {
+	zs := make([][]float64, len(tl.Tracks))
+	for i, t := range tl.Tracks {
+		zs[i] = boxToCenter(t.BBox)
+		k := angleIndex(len(zs[i]))
+		if i > 0 && k >= 0 {
+			zs[i][k] = unwrap(zs[i][k], zs[i-1][k])
+		}
+	}
+	return zs
+}
+
+//angleIndex is the position of the angle in boxes with n values, or -1 for boxes without angles
+func angleIndex(n int) int {
+	switch n {
+	case 5:
+		//oriented boxes [cx,cy,w,h,angle]
+		return 4
+	case 7:
+		//3D boxes [x,y,z,l,w,h,yaw]
+		return 6
+	}
+	return -1
+}
+
+//unwrap returns the angle equivalent to a closest to ref. Boxes turned by 180 degrees are the same
+func unwrap(a, ref float64) float64 {
+	return a - math.Pi*math.Round((a-ref)/math.Pi)
+}
+
+//trackVelocities returns, for each position of the tracklet, the velocities (per frame) of the values in zs
+//estimated by filtering forward up to it and backward from the end down to it
+func trackVelocities(tl Tracklet, zs [][]float64) ([][]float64, [][]float64) {
+	n := len(tl.Frames)
+	m := len(zs[0])
+	fwd := make([][]float64, n)
+	bwd := make([][]float64, n)
+	for i := range fwd {
+		fwd[i] = make([]float64, m)
+		bwd[i] = make([]float64, m)
+	}
+	for k := 0; k < m; k++ {
+		f := newVelocityFilter(zs[0][k])
+		for i := 1; i < n; i++ {
+			f.step(float64(tl.Frames[i]-tl.Frames[i-1]), zs[i][k])
+			fwd[i][k] = f.v
+		}
+		f = newVelocityFilter(zs[n-1][k])
+		for i := n - 2; i >= 0; i-- {
+			f.step(float64(tl.Frames[i+1]-tl.Frames[i]), zs[i][k])
+			//the backward filter runs in reversed time
+			bwd[i][k] = -f.v
+		}
+	}
+	return fwd, bwd
+}
+
+const (
+	//accelerationNoise is the process noise of velocityFilter, in px²/frame³
+	accelerationNoise = 1.0
+	//positionNoise is the measurement noise of velocityFilter, in px²
+	positionNoise = 10.0
+)
+
+//velocityFilter is a one dimensional constant velocity Kalman filter with state [p,v]
+type velocityFilter struct {
+	p, v          float64
+	ppp, ppv, pvv float64
+}
+
+func newVelocityFilter(p float64) *velocityFilter {
+	return &velocityFilter{p: p, ppp: positionNoise, pvv: 1000}
+}
+
+//step predicts dt frames ahead and updates with measurement z
+func (f *velocityFilter) step(dt float64, z float64) {
+	q := accelerationNoise
+	f.p = f.p + dt*f.v
+	ppp := f.ppp + 2*dt*f.ppv + dt*dt*f.pvv + q*dt*dt*dt/3
+	ppv := f.ppv + dt*f.pvv + q*dt*dt/2
+	pvv := f.pvv + q*dt
+
+	s := ppp + positionNoise
+	kp := ppp / s
+	kv := ppv / s
+	y := z - f.p
+	f.p = f.p + kp*y
+	f.v = f.v + kv*y
+	f.ppp = (1 - kp) * ppp
+	f.ppv = (1 - kp) * ppv
+	f.pvv = pvv - kv*ppv
+}
+
+//boxToCenter converts [x1,y1,x2,y2] boxes to [cx,cy,w,h]. Other boxes are copied as they are
+func boxToCenter(b []float64) []float64 {
+	if len(b) != 4 {
+		return append([]float64{}, b...)
+	}
+	w := b[2] - b[0]
+	h := b[3] - b[1]
+	return []float64{b[0] + w/2, b[1] + h/2, w, h}
+}
+
+func centerToBox(z []float64) []float64 {
+	if len(z) != 4 {
+		return z
+	}
+	return []float64{z[0] - z[2]/2, z[1] - z[3]/2, z[0] + z[2]/2, z[1] + z[3]/2}
+}
@@ -0,0 +1,94 @@
+//Package offline post processes a complete tracking result, for batch jobs where all frames are known in advance
+package offline
+
+import (
+	gosort "sort"
+
+	"github.com/flaviostutz/sort"
+)
+
+//Frame is the tracks reported for a frame
+type Frame struct {
+	Frame  int
+	Tracks []sort.Track
+}
+
+//Tracklet is the sequence of positions of a track ordered by frame
+type Tracklet struct {
+	ID     int64
+	Frames []int
+	Tracks []sort.Track
+}
+
+//Start is the first frame of the tracklet
+func (t Tracklet) Start() int {
+	return t.Frames[0]
+}
+
+//End is the last frame of the tracklet
+func (t Tracklet) End() int {
+	return t.Frames[len(t.Frames)-1]
+}
+
+//Tracklets splits a tracking result by track ID. Tracklets are ordered by ID
+func Tracklets(frames []Frame) []Tracklet {
+	byID := make(map[int64]*Tracklet)
+	ids := make([]int64, 0)
+	for _, f := range frames {
+		for _, t := range f.Tracks {
+			tl, ok := byID[t.ID]
+			if !ok {
+				tl = &Tracklet{ID: t.ID}
+				byID[t.ID] = tl
+				ids = append(ids, t.ID)
+			}
+			tl.Frames = append(tl.Frames, f.Frame)
+			tl.Tracks = append(tl.Tracks, t)
+		}
+	}
+	gosort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	r := make([]Tracklet, len(ids))
+	for i, id := range ids {
+		tl := byID[id]
+		gosort.Stable(byFrame(*tl))
+		r[i] = *tl
+	}
+	return r
+}
+
+//Merge returns frames with the tracklets tracks added. Frames that don't exist yet are created,
+//so that the result stays ordered by frame. Tracks of each frame are ordered by ID
+func Merge(frames []Frame, tracklets []Tracklet) []Frame {
+	index := make(map[int]int)
+	r := make([]Frame, len(frames))
+	for i, f := range frames {
+		r[i] = Frame{Frame: f.Frame, Tracks: append([]sort.Track{}, f.Tracks...)}
+		index[f.Frame] = i
+	}
+	for _, tl := range tracklets {
+		for i, fn := range tl.Frames {
+			fi, ok := index[fn]
+			if !ok {
+				fi = len(r)
+				index[fn] = fi
+				r = append(r, Frame{Frame: fn, Tracks: make([]sort.Track, 0)})
+			}
+			r[fi].Tracks = append(r[fi].Tracks, tl.Tracks[i])
+		}
+	}
+	gosort.SliceStable(r, func(i, j int) bool { return r[i].Frame < r[j].Frame })
+	for _, f := range r {
+		tracks := f.Tracks
+		gosort.SliceStable(tracks, func(i, j int) bool { return tracks[i].ID < tracks[j].ID })
+	}
+	return r
+}
+
+type byFrame Tracklet
+
+func (b byFrame) Len() int           { return len(b.Frames) }
+func (b byFrame) Less(i, j int) bool { return b.Frames[i] < b.Frames[j] }
+func (b byFrame) Swap(i, j int) {
+	b.Frames[i], b.Frames[j] = b.Frames[j], b.Frames[i]
+	b.Tracks[i], b.Tracks[j] = b.Tracks[j], b.Tracks[i]
+}
@@ -0,0 +1,209 @@
+package offline
+
+import (
+	"math"
+	"testing"
+
+	"github.com/flaviostutz/sort"
+)
+
+func track(id int64, x float64) sort.Track {
+	return sort.Track{ID: id, BBox: []float64{x, 10, x + 20, 50}, Score: 1, Class: "person"}
+}
+
+//track 1 moves 2px/frame and misses frames 4-6. track 2 misses frames 2-9, more than maxGap
+func gapFrames() []Frame {
+	frames := make([]Frame, 0)
+	for f := 1; f <= 10; f++ {
+		tracks := make([]sort.Track, 0)
+		if f < 4 || f > 6 {
+			tracks = append(tracks, track(1, float64(2*f)))
+		}
+		if f == 1 || f == 10 {
+			tracks = append(tracks, track(2, 200))
+		}
+		frames = append(frames, Frame{Frame: f, Tracks: tracks})
+	}
+	return frames
+}
+
+func TestTracklets(t *testing.T) {
+	tls := Tracklets(gapFrames())
+	if len(tls) != 2 || tls[0].ID != 1 || tls[1].ID != 2 {
+		t.Fatalf("Unexpected tracklets %v", tls)
+	}
+	if len(tls[0].Frames) != 7 || tls[0].Start() != 1 || tls[0].End() != 10 {
+		t.Errorf("Unexpected tracklet 1 frames %v", tls[0].Frames)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	for _, method := range []string{"linear", "kalman"} {
+		frames, err := Interpolate(gapFrames(), 3, method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(frames) != 10 {
+			t.Fatalf("%s: frames should be kept. len=%d", method, len(frames))
+		}
+		for f := 4; f <= 6; f++ {
+			tracks := frames[f-1].Tracks
+			if len(tracks) != 1 || tracks[0].ID != 1 || !tracks[0].Interpolated || tracks[0].Class != "person" {
+				t.Fatalf("%s: frame %d not filled. tracks=%v", method, f, tracks)
+			}
+			//constant motion is followed by both methods
+			if math.Abs(tracks[0].BBox[0]-float64(2*f)) > 0.5 || math.Abs(tracks[0].BBox[3]-50) > 0.5 {
+				t.Errorf("%s: unexpected interpolated box in frame %d. bbox=%v", method, f, tracks[0].BBox)
+			}
+		}
+		for f := 2; f <= 9; f++ {
+			for _, trk := range frames[f-1].Tracks {
+				if trk.ID == 2 {
+					t.Errorf("%s: gap larger than maxGap should not be filled. frame=%d", method, f)
+				}
+			}
+		}
+		if frames[0].Tracks[0].Interpolated {
+			t.Errorf("%s: original tracks should not be marked", method)
+		}
+	}
+
+	_, err := Interpolate(gapFrames(), 3, "spline")
+	if err == nil {
+		t.Errorf("Unknown methods should be rejected")
+	}
+}
+
+func TestInterpolateOrientedBoxes(t *testing.T) {
+	//the angle turns across the end of [-pi/2,pi/2)
+	frames := []Frame{
+		{Frame: 1, Tracks: []sort.Track{{ID: 1, BBox: []float64{10, 10, 20, 10, 1.5}}}},
+		{Frame: 2, Tracks: []sort.Track{}},
+		{Frame: 3, Tracks: []sort.Track{{ID: 1, BBox: []float64{14, 10, 20, 10, -1.5}}}},
+	}
+	for _, method := range []string{"linear", "kalman"} {
+		r, err := Interpolate(frames, 3, method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := r[1].Tracks[0].BBox
+		if len(b) != 5 || math.Abs(b[0]-12) > 0.5 || math.Abs(math.Abs(b[4])-math.Pi/2) > 0.05 {
+			t.Errorf("%s: unexpected interpolated box %v", method, b)
+		}
+	}
+
+	_, err := Stitch(frames, nil, DefaultStitchConfig())
+	if err == nil {
+		t.Errorf("Stitch should reject oriented boxes")
+	}
+}
+
+func TestSmooth(t *testing.T) {
+	s, err := sort.NewSORT(sort.WithHistory(), sort.WithMinUpdatesUsePrediction(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := make([]Frame, 0)
+	for f := 1; f <= 10; f++ {
+		//boxes jitter around a constant motion
+		x := float64(2*f) + float64(f%2)*4
+		err = s.UpdateDetections([]sort.Detection{{BBox: []float64{x, 10, x + 20, 50}, Score: 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, Frame{Frame: f, Tracks: s.Tracks()})
+	}
+	smoothed, err := Smooth(s, frames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jitter, sjitter float64
+	for i := 5; i < 9; i++ {
+		jitter += math.Abs(frames[i+1].Tracks[0].BBox[0] - frames[i].Tracks[0].BBox[0] - 2)
+		sjitter += math.Abs(smoothed[i+1].Tracks[0].BBox[0] - smoothed[i].Tracks[0].BBox[0] - 2)
+	}
+	if sjitter >= jitter {
+		t.Errorf("smoothed boxes should jitter less. raw=%f smoothed=%f", jitter, sjitter)
+	}
+
+	s, _ = sort.NewSORT()
+	s.Update([][]float64{{10, 10, 30, 50}})
+	_, err = Smooth(s, frames)
+	if err == nil {
+		t.Errorf("sessions without history should be rejected")
+	}
+}
+
+//walk returns the tracks of a box moving dx per frame from x in frames [from,to]
+func walk(frames map[int][]sort.Track, id int64, from, to int, x, dx float64) {
+	for f := from; f <= to; f++ {
+		frames[f] = append(frames[f], track(id, x+dx*float64(f-from)))
+	}
+}
+
+func toFrames(m map[int][]sort.Track, last int) []Frame {
+	frames := make([]Frame, 0)
+	for f := 1; f <= last; f++ {
+		frames = append(frames, Frame{Frame: f, Tracks: m[f]})
+	}
+	return frames
+}
+
+func TestStitch(t *testing.T) {
+	m := make(map[int][]sort.Track)
+	walk(m, 1, 1, 10, 0, 2)
+	//reappears after 5 frames where track 1 would be
+	walk(m, 2, 16, 25, 30, 2)
+	//unrelated track far away
+	walk(m, 3, 16, 25, 500, -2)
+	//track 2 reappears again
+	walk(m, 4, 30, 35, 58, 2)
+	frames := toFrames(m, 35)
+
+	remap, err := Stitch(frames, nil, DefaultStitchConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remap) != 2 || remap[2] != 1 || remap[4] != 1 {
+		t.Fatalf("Unexpected remap table %v", remap)
+	}
+	stitched := Remap(frames, remap)
+	tls := Tracklets(stitched)
+	if len(tls) != 2 || tls[0].ID != 1 || tls[0].End() != 35 {
+		t.Errorf("Unexpected stitched tracklets %v", tls)
+	}
+
+	cfg := DefaultStitchConfig()
+	cfg.MaxGap = 3
+	remap, _ = Stitch(frames, nil, cfg)
+	if len(remap) != 0 {
+		t.Errorf("Gaps longer than MaxGap should not be stitched. remap=%v", remap)
+	}
+
+	cfg.MaxGap = 0
+	_, err = Stitch(frames, nil, cfg)
+	if err == nil {
+		t.Errorf("Invalid config should be rejected")
+	}
+}
+
+func TestStitchAppearance(t *testing.T) {
+	m := make(map[int][]sort.Track)
+	walk(m, 1, 1, 10, 0, 2)
+	//two candidates equally close to the end of track 1
+	walk(m, 2, 14, 20, 30, 2)
+	walk(m, 3, 14, 20, 30, 2)
+	frames := toFrames(m, 20)
+
+	emb := map[int64][]float64{1: {1, 0, 0}, 2: {0, 1, 0}, 3: {0.9, 0.1, 0}}
+	remap, err := Stitch(frames, emb, DefaultStitchConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remap[3] != 1 {
+		t.Errorf("Most similar tracklet should be stitched. remap=%v", remap)
+	}
+	if _, ok := remap[2]; ok {
+		t.Errorf("Overlapping tracklet should not be stitched. remap=%v", remap)
+	}
+}
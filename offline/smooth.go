@@ -0,0 +1,38 @@
+package offline
+
+import (
+	"fmt"
+
+	"github.com/flaviostutz/sort"
+)
+
+//Smooth replaces the boxes of frames, the tracks reported by s, with the Rauch-Tung-Striebel smoothed
+//estimates of their trackers. s must have been created WithHistory and have tracked all frames
+func Smooth(s *sort.SORT, frames []Frame) ([]Frame, error) {
+	smoothed := make(map[int64]map[int][]float64)
+	for _, trks := range [][]*sort.KalmanBoxTracker{s.Finished, s.Trackers} {
+		for _, trk := range trks {
+			if trk.History == nil {
+				return nil, fmt.Errorf("tracker %d has no history. create the session WithHistory", trk.ID)
+			}
+			boxes := make(map[int][]float64)
+			for _, e := range trk.History.Smoothed() {
+				boxes[e.Frame] = e.BBox
+			}
+			smoothed[trk.ID] = boxes
+		}
+	}
+
+	r := make([]Frame, len(frames))
+	for i, f := range frames {
+		tracks := make([]sort.Track, len(f.Tracks))
+		for j, t := range f.Tracks {
+			if b, ok := smoothed[t.ID][f.Frame]; ok {
+				t.BBox = b
+			}
+			tracks[j] = t
+		}
+		r[i] = Frame{Frame: f.Frame, Tracks: tracks}
+	}
+	return r, nil
+}
@@ -0,0 +1,216 @@
+package offline
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cpmech/gosl/graph"
+	"github.com/flaviostutz/sort"
+	"github.com/sirupsen/logrus"
+)
+
+//StitchConfig has the parameters of Stitch
+type StitchConfig struct {
+	//MaxGap is the maximum number of frames between the end of a tracklet and the start of the next one
+	MaxGap int
+	//MaxDistance gates the distance between the extrapolated end of a tracklet and the start of the next one,
+	//measured in box diagonals
+	MaxDistance float64
+	//MaxCost gates the total cost of a link
+	MaxCost float64
+	//Weights of each cost term
+	MotionWeight     float64
+	GapWeight        float64
+	SizeWeight       float64
+	AppearanceWeight float64
+	//VelocityFrames is the number of last positions used to estimate the velocity of a tracklet
+	VelocityFrames int
+}
+
+//DefaultStitchConfig returns parameters that work for pedestrians at 25-30 fps
+func DefaultStitchConfig() StitchConfig {
+	return StitchConfig{
+		MaxGap:           60,
+		MaxDistance:      2,
+		MaxCost:          2,
+		MotionWeight:     1,
+		GapWeight:        0.5,
+		SizeWeight:       1,
+		AppearanceWeight: 1,
+		VelocityFrames:   10,
+	}
+}
+
+//Validate checks that parameters are in their valid ranges
+func (c StitchConfig) Validate() error {
+	if c.MaxGap < 1 {
+		return fmt.Errorf("maxGap must be >= 1")
+	}
+	if c.MaxDistance <= 0 || c.MaxCost <= 0 {
+		return fmt.Errorf("maxDistance and maxCost must be > 0")
+	}
+	if c.MotionWeight < 0 || c.GapWeight < 0 || c.SizeWeight < 0 || c.AppearanceWeight < 0 {
+		return fmt.Errorf("weights must be >= 0")
+	}
+	if c.VelocityFrames < 2 {
+		return fmt.Errorf("velocityFrames must be >= 2")
+	}
+	return nil
+}
+
+//infeasible is the assignment cost of pairs that can't be linked
+const infeasible = 1e9
+
+//Stitch finds tracklets that are the continuation of another one after an occlusion and returns a table
+//that maps their IDs to the ID of the first tracklet of the chain. Pairs where a tracklet ends before
+//the other starts are scored by motion extrapolation, time gap, size consistency and, when embeddings
+//has a feature vector for both tracker IDs, appearance similarity. Links are chosen by a min-cost assignment.
+//Only [x1,y1,x2,y2] boxes are supported
+func Stitch(frames []Frame, embeddings map[int64][]float64, cfg StitchConfig) (map[int64]int64, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range frames {
+		for _, t := range f.Tracks {
+			if len(t.BBox) != 4 {
+				return nil, fmt.Errorf("stitching needs 4 value boxes. track=%d frame=%d", t.ID, f.Frame)
+			}
+		}
+	}
+	tls := Tracklets(frames)
+	n := len(tls)
+	remap := make(map[int64]int64)
+	if n < 2 {
+		return remap, nil
+	}
+
+	cost := make([][]float64, n)
+	for i := range cost {
+		cost[i] = make([]float64, n)
+		for j := range cost[i] {
+			cost[i][j] = infeasible
+			if i != j {
+				c, ok := linkCost(tls[i], tls[j], embeddings, cfg)
+				if ok {
+					cost[i][j] = c
+				}
+			}
+		}
+	}
+
+	mk := graph.Munkres{}
+	mk.Init(n, n)
+	mk.SetCostMatrix(cost)
+	mk.Run()
+
+	//next[i] is the tracklet that continues tracklet i
+	next := make(map[int]int)
+	prev := make(map[int]bool)
+	for i, j := range mk.Links {
+		if j != -1 && cost[i][j] < infeasible {
+			next[i] = j
+			prev[j] = true
+			logrus.Debugf("Tracklets stitched. id=%d next=%d cost=%f", tls[i].ID, tls[j].ID, cost[i][j])
+		}
+	}
+	for i := range tls {
+		if prev[i] {
+			continue
+		}
+		//i starts a chain
+		for j, ok := next[i]; ok; j, ok = next[j] {
+			remap[tls[j].ID] = tls[i].ID
+		}
+	}
+	return remap, nil
+}
+
+//Remap returns frames with track IDs replaced according to remap
+func Remap(frames []Frame, remap map[int64]int64) []Frame {
+	r := make([]Frame, len(frames))
+	for i, f := range frames {
+		tracks := make([]sort.Track, len(f.Tracks))
+		for j, t := range f.Tracks {
+			if id, ok := remap[t.ID]; ok {
+				t.ID = id
+			}
+			tracks[j] = t
+		}
+		r[i] = Frame{Frame: f.Frame, Tracks: tracks}
+	}
+	return r
+}
+
+//linkCost scores tracklet b as the continuation of a. ok is false when they can't be linked
+func linkCost(a, b Tracklet, embeddings map[int64][]float64, cfg StitchConfig) (float64, bool) {
+	gap := b.Start() - a.End()
+	if gap < 1 || gap > cfg.MaxGap {
+		return 0, false
+	}
+	last := a.Tracks[len(a.Tracks)-1]
+	first := b.Tracks[0]
+	if last.Class != "" && first.Class != "" && last.Class != first.Class {
+		return 0, false
+	}
+
+	za := boxToCenter(last.BBox)
+	zb := boxToCenter(first.BBox)
+	diag := math.Hypot(za[2], za[3])
+	if diag <= 0 {
+		return 0, false
+	}
+	v := velocity(a, cfg.VelocityFrames)
+	dx := za[0] + v[0]*float64(gap) - zb[0]
+	dy := za[1] + v[1]*float64(gap) - zb[1]
+	motion := math.Hypot(dx, dy) / diag
+	if motion > cfg.MaxDistance {
+		return 0, false
+	}
+
+	size := 0.0
+	if za[2]*za[3] > 0 && zb[2]*zb[3] > 0 {
+		size = math.Abs(math.Log(zb[2] * zb[3] / (za[2] * za[3])))
+	}
+
+	appearance := 0.0
+	ea, eb := embeddings[a.ID], embeddings[b.ID]
+	if len(ea) > 0 && len(ea) == len(eb) {
+		appearance = 1 - cosine(ea, eb)
+	}
+
+	c := cfg.MotionWeight*motion + cfg.GapWeight*float64(gap)/float64(cfg.MaxGap) + cfg.SizeWeight*size + cfg.AppearanceWeight*appearance
+	if c > cfg.MaxCost {
+		return 0, false
+	}
+	return c, true
+}
+
+//velocity is the center displacement per frame over the last positions of a tracklet
+func velocity(t Tracklet, frames int) []float64 {
+	n := len(t.Frames)
+	first := n - frames
+	if first < 0 {
+		first = 0
+	}
+	df := float64(t.Frames[n-1] - t.Frames[first])
+	if df <= 0 {
+		return []float64{0, 0}
+	}
+	z0 := boxToCenter(t.Tracks[first].BBox)
+	z1 := boxToCenter(t.Tracks[n-1].BBox)
+	return []float64{(z1[0] - z0[0]) / df, (z1[1] - z0[1]) / df}
+}
+
+func cosine(a, b []float64) float64 {
+	var ab, aa, bb float64
+	for i := range a {
+		ab += a[i] * b[i]
+		aa += a[i] * a[i]
+		bb += b[i] * b[i]
+	}
+	if aa == 0 || bb == 0 {
+		return 0
+	}
+	return ab / math.Sqrt(aa*bb)
+}
@@ -0,0 +1,203 @@
+package sort
+
+import (
+	"fmt"
+)
+
+//Option customizes a SORT session created with NewSORT
+type Option func(*SORT) error
+
+//WithConfig replaces all parameters with the ones in c
+func WithConfig(c Config) Option {
+	return func(s *SORT) error {
+		s.config = c
+		return nil
+	}
+}
+
+//WithPreset replaces all parameters with the ones from a named preset
+func WithPreset(name string) Option {
+	return func(s *SORT) error {
+		c, err := Preset(name)
+		if err != nil {
+			return err
+		}
+		s.config = c
+		return nil
+	}
+}
+
+//WithHistory keeps the filter history of every tracker, including removed ones, for offline smoothing.
+//Memory grows with the number of frames processed, so it shouldn't be used in live sessions
+func WithHistory() Option {
+	return func(s *SORT) error {
+		s.history = true
+		return nil
+	}
+}
+
+//WithFrameSize sets the image size in pixels. See Config.FrameWidth
+func WithFrameSize(width, height float64) Option {
+	return func(s *SORT) error {
+		s.config.FrameWidth = width
+		s.config.FrameHeight = height
+		return nil
+	}
+}
+
+//WithKeypointSmoothing filters track keypoints with a One Euro filter. See DefaultOneEuro
+func WithKeypointSmoothing(f OneEuro) Option {
+	return func(s *SORT) error {
+		s.config.KeypointSmoothing = &f
+		return nil
+	}
+}
+
+//WithKeypointSigmas sets the per keypoint constants of the oks cost function
+func WithKeypointSigmas(sigmas []float64) Option {
+	return func(s *SORT) error {
+		s.config.KeypointSigmas = sigmas
+		return nil
+	}
+}
+
+//WithDeletionPolicy replaces the rule that removes trackers. See DefaultDeletionPolicy and AdaptiveDeletionPolicy
+func WithDeletionPolicy(p DeletionPolicy) Option {
+	return func(s *SORT) error {
+		if p == nil {
+			return fmt.Errorf("deletion policy must not be nil")
+		}
+		v, ok := p.(interface{ Validate() error })
+		if ok {
+			err := v.Validate()
+			if err != nil {
+				return err
+			}
+		}
+		s.deletion = p
+		return nil
+	}
+}
+
+//WithConfidenceDecay sets the fraction of the track confidence lost for each frame without a matched detection
+func WithConfidenceDecay(decay float64) Option {
+	return func(s *SORT) error {
+		s.config.ConfidenceDecay = decay
+		return nil
+	}
+}
+
+//WithMinConfidence removes coasting trackers whose confidence falls below min instead of using MaxPredictsWithoutUpdate
+func WithMinConfidence(min float64) Option {
+	return func(s *SORT) error {
+		s.config.MinConfidence = min
+		return nil
+	}
+}
+
+//WithReportConfidence makes Tracks report matched trackers whose confidence is at least min
+func WithReportConfidence(min float64) Option {
+	return func(s *SORT) error {
+		s.config.ReportConfidence = min
+		return nil
+	}
+}
+
+//WithVelocitySmoothing sets the weight of the previous value in the exponential smoothing of track velocities
+func WithVelocitySmoothing(alpha float64) Option {
+	return func(s *SORT) error {
+		s.config.VelocitySmoothing = alpha
+		return nil
+	}
+}
+
+//WithCalibration enables ground plane velocities in meters per second
+func WithCalibration(c Calibration) Option {
+	return func(s *SORT) error {
+		s.config.Calibration = &c
+		return nil
+	}
+}
+
+//WithMaxPredictsWithoutUpdate sets how many frames a tracker survives without being matched to a detection
+func WithMaxPredictsWithoutUpdate(n int) Option {
+	return func(s *SORT) error {
+		s.config.MaxPredictsWithoutUpdate = n
+		return nil
+	}
+}
+
+//WithMinUpdatesUsePrediction sets how many updates a tracker needs before its prediction is used for association
+func WithMinUpdatesUsePrediction(n int) Option {
+	return func(s *SORT) error {
+		s.config.MinUpdatesUsePrediction = n
+		return nil
+	}
+}
+
+//WithIOUThreshold sets the minimum score for a detection to be matched to a tracker
+func WithIOUThreshold(t float64) Option {
+	return func(s *SORT) error {
+		s.config.IOUThreshold = t
+		return nil
+	}
+}
+
+//WithMotionModel sets the Kalman model used by new trackers. See NewMotionModel
+func WithMotionModel(name string) Option {
+	return func(s *SORT) error {
+		s.config.MotionModel = name
+		return nil
+	}
+}
+
+//WithMaxDistance prevents matching detections and trackers farther apart on the ground plane. See Config.MaxDistance
+func WithMaxDistance(d float64) Option {
+	return func(s *SORT) error {
+		s.config.MaxDistance = d
+		return nil
+	}
+}
+
+//WithMaxPointDistance sets the gate of the euclidean and mahalanobis cost functions. See Config.MaxPointDistance
+func WithMaxPointDistance(d float64) Option {
+	return func(s *SORT) error {
+		s.config.MaxPointDistance = d
+		return nil
+	}
+}
+
+//WithScoreNoise scales the measurement noise of each tracker update with the detection score. See NewScoreNoise
+func WithScoreNoise(name string) Option {
+	return func(s *SORT) error {
+		s.config.ScoreNoise = name
+		return nil
+	}
+}
+
+//WithScoreNoiseFunc scales the measurement noise of each tracker update by f(score) instead of Config.ScoreNoise
+func WithScoreNoiseFunc(f ScoreNoise) Option {
+	return func(s *SORT) error {
+		if f == nil {
+			return fmt.Errorf("score noise must not be nil")
+		}
+		s.scoreNoise = f
+		return nil
+	}
+}
+
+//WithProcessNoise scales the process noise of the motion model
+func WithProcessNoise(q float64) Option {
+	return func(s *SORT) error {
+		s.config.ProcessNoise = q
+		return nil
+	}
+}
+
+//WithCostFunction sets how detections are scored against trackers. See NewCostFunction
+func WithCostFunction(name string) Option {
+	return func(s *SORT) error {
+		s.config.CostFunction = name
+		return nil
+	}
+}
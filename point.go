@@ -0,0 +1,145 @@
+package sort
+
+import (
+	"math"
+
+	"github.com/flaviostutz/kalman"
+	"github.com/konimarti/lti"
+	"gonum.org/v1/gonum/mat"
+)
+
+//ConstantVelocityPoint tracks points [x,y,r] of detectors without boxes, like radars or blob detectors.
+//r is the object radius, 0 when unknown. State is [x,y,r,vx,vy]
+type ConstantVelocityPoint struct {
+	//ProcessNoise scales the process noise covariance Q
+	ProcessNoise float64
+}
+
+//Name identifies the model in configurations
+func (m ConstantVelocityPoint) Name() string {
+	return "constant-velocity-point"
+}
+
+//System returns the discrete linear system, its noise and the initial state covariance
+func (m ConstantVelocityPoint) System() (lti.Discrete, kalman.Noise, *mat.Dense) {
+	q := m.ProcessNoise
+	sys := lti.Discrete{
+		Ad: mat.NewDense(5, 5, []float64{
+			1, 0, 0, 1, 0,
+			0, 1, 0, 0, 1,
+			0, 0, 1, 0, 0,
+			0, 0, 0, 1, 0,
+			0, 0, 0, 0, 1}),
+		Bd: mat.NewDense(5, 5, nil),
+		C: mat.NewDense(3, 5, []float64{
+			1, 0, 0, 0, 0,
+			0, 1, 0, 0, 0,
+			0, 0, 1, 0, 0}),
+		D: mat.NewDense(3, 5, nil),
+	}
+	qd := []float64{q, q, q, 0.01 * q, 0.01 * q}
+	rd := []float64{1, 1, 10}
+	pd := []float64{10, 10, 10, 1000, 1000}
+	nse := kalman.Noise{Q: diagonal(qd), R: diagonal(rd)}
+	return sys, nse, diagonal(pd)
+}
+
+//ToMeasurement converts a point to the measurement vector [x,y,r]
+func (m ConstantVelocityPoint) ToMeasurement(bbox []float64) []float64 {
+	return []float64{bbox[0], bbox[1], bbox[2]}
+}
+
+//ToBox converts a state vector back to a point [x,y,r]
+func (m ConstantVelocityPoint) ToBox(x mat.Vector) []float64 {
+	return []float64{x.AtVec(0), x.AtVec(1), math.Max(x.AtVec(2), 0)}
+}
+
+//Velocity returns the point velocity in pixels per frame
+func (m ConstantVelocityPoint) Velocity(x mat.Vector) []float64 {
+	return []float64{x.AtVec(3), x.AtVec(4)}
+}
+
+//Constrain avoids negative radiuses
+func (m ConstantVelocityPoint) Constrain(x *mat.VecDense) {
+	if x.AtVec(2) < 0 {
+		x.SetVec(2, 0)
+	}
+}
+
+//BoxSize is the number of values of a point
+func (m ConstantVelocityPoint) BoxSize() int {
+	return 3
+}
+
+//ValidBox accepts any point with a radius >= 0
+func (m ConstantVelocityPoint) ValidBox(bbox []float64) bool {
+	return bbox[2] >= 0
+}
+
+//EnclosingBox returns the [x1,y1,x2,y2] box containing the point circle
+func (m ConstantVelocityPoint) EnclosingBox(bbox []float64) []float64 {
+	return []float64{bbox[0] - bbox[2], bbox[1] - bbox[2], bbox[0] + bbox[2], bbox[1] + bbox[2]}
+}
+
+//CenterDistance is the euclidean distance between two points [x,y,r]
+func CenterDistance(p1 []float64, p2 []float64) float64 {
+	return math.Hypot(p1[0]-p2[0], p1[1]-p2[1])
+}
+
+//distanceCost tells whether a cost function is a distance gated by MaxPointDistance instead of a score
+func distanceCost(name string) bool {
+	return name == "euclidean" || name == "mahalanobis"
+}
+
+//center returns the center of a box of a motion model. Boxes not on the image start with their center
+func center(m MotionModel, bbox []float64) (float64, float64) {
+	b := imageBox(m, bbox)
+	if b == nil {
+		return bbox[0], bbox[1]
+	}
+	return (b[0] + b[2]) / 2, (b[1] + b[3]) / 2
+}
+
+//positionCovariance returns the covariance [sxx,sxy,syy] of the first two measurement values predicted for the
+//current frame, which are the object position for all motion models. Frames the tracker has been coasting widen it
+func (k *KalmanBoxTracker) positionCovariance() []float64 {
+	sys, nse, _ := k.MotionModel.System()
+	//KalmanCtx.P is the covariance predicted one step after the last update
+	p := mat.DenseCopyOf(k.KalmanCtx.P)
+	for t := 1; t < k.PredictsSinceUpdate; t++ {
+		var pn mat.Dense
+		pn.Product(sys.Ad, p, sys.Ad.T())
+		pn.Add(&pn, nse.Q)
+		p = &pn
+	}
+	var s mat.Dense
+	s.Product(sys.C, p, sys.C.T())
+	s.Add(&s, nse.R)
+	return []float64{s.At(0, 0), s.At(0, 1), s.At(1, 1)}
+}
+
+//mahalanobis is the distance between the positions of a detection and the reference box ref of a tracker
+//in standard deviations of the position covariance cov
+func mahalanobis(m MotionModel, det []float64, ref []float64, cov []float64) float64 {
+	z1, z2 := m.ToMeasurement(det), m.ToMeasurement(ref)
+	dx, dy := z1[0]-z2[0], z1[1]-z2[1]
+	d := cov[0]*cov[2] - cov[1]*cov[1]
+	if d <= 0 {
+		return math.Inf(1)
+	}
+	return math.Sqrt((cov[2]*dx*dx - 2*cov[1]*dx*dy + cov[0]*dy*dy) / d)
+}
+
+//pointDistance is the distance of a detection to the reference box ref of a tracker with a distance cost function.
+//cov caches the position covariance of the tracker
+func pointDistance(c Config, trk *KalmanBoxTracker, det []float64, ref []float64, cov *[]float64) float64 {
+	if c.CostFunction == "mahalanobis" {
+		if *cov == nil {
+			*cov = trk.positionCovariance()
+		}
+		return mahalanobis(trk.MotionModel, det, ref, *cov)
+	}
+	x1, y1 := center(trk.MotionModel, det)
+	x2, y2 := center(trk.MotionModel, ref)
+	return math.Hypot(x1-x2, y1-y2)
+}
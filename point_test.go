@@ -0,0 +1,126 @@
+package sort
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointTracking(t *testing.T) {
+	for _, cost := range []string{"euclidean", "mahalanobis"} {
+		gate := 30.0
+		if cost == "mahalanobis" {
+			gate = 5
+		}
+		s, err := NewSORT(WithMotionModel("constant-velocity-point"), WithCostFunction(cost), WithMaxPointDistance(gate))
+		if err != nil {
+			t.Fatal(err)
+		}
+		//two radar targets crossing 10 pixels apart
+		targets := map[int64]int{}
+		for f := 0; f < 40; f++ {
+			a := []float64{100 + 5*float64(f), 200, 2, 0.9}
+			b := []float64{300 - 5*float64(f), 210, 0, 0.8}
+			dets := [][]float64{a, b}
+			if f%2 == 1 {
+				dets = [][]float64{b, a}
+			}
+			err = s.Update(dets)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tr := range s.Tracks() {
+				target := 0
+				if tr.BBox[1] == b[1] {
+					target = 1
+				}
+				prev, ok := targets[tr.ID]
+				if ok && prev != target {
+					t.Fatalf("%s: track switched targets at frame %d. id=%d", cost, f, tr.ID)
+				}
+				targets[tr.ID] = target
+				if len(tr.BBox) != 3 || tr.Velocity == nil {
+					t.Errorf("%s: tracks should report points and velocities. track=%+v", cost, tr)
+				}
+			}
+		}
+		if len(targets) != 2 {
+			t.Errorf("%s: expected 2 tracks, got %d", cost, len(targets))
+		}
+		for _, trk := range s.Trackers {
+			if math.Abs(math.Abs(trk.Velocity[0])-5) > 0.5 {
+				t.Errorf("%s: wrong velocity %v", cost, trk.Velocity)
+			}
+		}
+
+		//points beyond the gate start new trackers
+		ids := len(s.Trackers)
+		err = s.UpdateDetections([]Detection{{BBox: []float64{305, 200}, Score: 0.9}, {BBox: []float64{500, 500}, Score: 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Trackers) != ids+1 {
+			t.Errorf("%s: only far points should start trackers. trackers=%d", cost, len(s.Trackers))
+		}
+	}
+}
+
+func TestMahalanobisGate(t *testing.T) {
+	s, err := NewSORT(WithMotionModel("constant-velocity-point"), WithCostFunction("mahalanobis"), WithMaxPointDistance(3),
+		WithMaxPredictsWithoutUpdate(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := 0; f < 10; f++ {
+		err = s.Update([][]float64{{100, 100, 0}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	trk := s.Trackers[0]
+	cov := trk.positionCovariance()
+	//a jump the gate of a tracker matched in the last frame rejects
+	err = s.Update([][]float64{{115, 100, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Trackers) != 2 {
+		t.Fatalf("jump should not be matched")
+	}
+	s.Trackers = s.Trackers[:1]
+	for f := 0; f < 10; f++ {
+		err = s.Update([][]float64{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if trk.positionCovariance()[0] <= cov[0] {
+		t.Errorf("uncertainty should grow while coasting")
+	}
+	//after coasting the same jump is within the gate
+	err = s.Update([][]float64{{115, 100, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Trackers) != 1 || trk.FramesSinceUpdate != 0 {
+		t.Errorf("coasting tracker should match a farther point")
+	}
+
+	_, err = NewSORT(WithCostFunction("euclidean"))
+	if err == nil {
+		t.Errorf("distance costs without maxPointDistance should be rejected")
+	}
+	//points have no overlap to score
+	for _, cost := range []string{"iou", "giou", "rotated-iou", "iou-3d"} {
+		_, err = NewSORT(WithMotionModel("constant-velocity-point"), WithCostFunction(cost))
+		if err == nil {
+			t.Errorf("cost function %s should be rejected for points", cost)
+		}
+	}
+	s, err = NewSORT(WithPreset("point"))
+	if err != nil {
+		t.Fatalf("point preset should be valid. err=%s", err)
+	}
+	if s.BoxSize() != 3 {
+		t.Errorf("point preset should track points. boxSize=%d", s.BoxSize())
+	}
+}
@@ -0,0 +1,74 @@
+package sort
+
+import (
+	"math"
+)
+
+//rectCorners returns the counter clockwise corners of a rectangle centered at cx,cy with length l along angle and width w
+func rectCorners(cx, cy, l, w, angle float64) [][2]float64 {
+	c, s := math.Cos(angle), math.Sin(angle)
+	dx := [][2]float64{{l / 2, w / 2}, {-l / 2, w / 2}, {-l / 2, -w / 2}, {l / 2, -w / 2}}
+	r := make([][2]float64, 4)
+	for i, d := range dx {
+		r[i] = [2]float64{cx + d[0]*c - d[1]*s, cy + d[0]*s + d[1]*c}
+	}
+	return r
+}
+
+//polygonArea is the area of a simple polygon (shoelace formula)
+func polygonArea(p [][2]float64) float64 {
+	a := 0.0
+	for i := range p {
+		j := (i + 1) % len(p)
+		a += p[i][0]*p[j][1] - p[j][0]*p[i][1]
+	}
+	return math.Abs(a) / 2
+}
+
+//clipPolygon returns the intersection of a polygon with a convex counter clockwise polygon (Sutherland-Hodgman)
+func clipPolygon(subject, clip [][2]float64) [][2]float64 {
+	out := subject
+	for i := range clip {
+		if len(out) == 0 {
+			break
+		}
+		a, b := clip[i], clip[(i+1)%len(clip)]
+		inside := func(p [2]float64) bool {
+			return (b[0]-a[0])*(p[1]-a[1])-(b[1]-a[1])*(p[0]-a[0]) >= 0
+		}
+		in := out
+		out = make([][2]float64, 0, len(in)+1)
+		for j := range in {
+			p, q := in[j], in[(j+1)%len(in)]
+			pin, qin := inside(p), inside(q)
+			if pin {
+				out = append(out, p)
+			}
+			if pin != qin {
+				out = append(out, lineIntersection(p, q, a, b))
+			}
+		}
+	}
+	return out
+}
+
+//lineIntersection is the intersection of segment p-q with the line through a and b
+func lineIntersection(p, q, a, b [2]float64) [2]float64 {
+	d1 := [2]float64{q[0] - p[0], q[1] - p[1]}
+	d2 := [2]float64{b[0] - a[0], b[1] - a[1]}
+	den := d1[0]*d2[1] - d1[1]*d2[0]
+	if den == 0 {
+		return p
+	}
+	t := ((a[0]-p[0])*d2[1] - (a[1]-p[1])*d2[0]) / den
+	return [2]float64{p[0] + t*d1[0], p[1] + t*d1[1]}
+}
+
+//wrapAngle returns the equivalent angle in [-pi,pi)
+func wrapAngle(a float64) float64 {
+	a = math.Mod(a+math.Pi, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a - math.Pi
+}
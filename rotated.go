@@ -0,0 +1,167 @@
+package sort
+
+import (
+	"math"
+
+	"github.com/flaviostutz/kalman"
+	"github.com/konimarti/lti"
+	"gonum.org/v1/gonum/mat"
+)
+
+//OrientedBox is a rotated rectangle on the image. Angle is in radians from the x axis (clockwise on the image,
+//as y grows downwards) and W is measured along it. As a slice it is [cx,cy,w,h,angle]
+type OrientedBox struct {
+	CX    float64
+	CY    float64
+	W     float64
+	H     float64
+	Angle float64
+}
+
+//NewOrientedBox creates an oriented box from [cx,cy,w,h,angle]
+func NewOrientedBox(b []float64) OrientedBox {
+	return OrientedBox{CX: b[0], CY: b[1], W: b[2], H: b[3], Angle: b[4]}
+}
+
+//Slice returns the box as [cx,cy,w,h,angle]
+func (o OrientedBox) Slice() []float64 {
+	return []float64{o.CX, o.CY, o.W, o.H, o.Angle}
+}
+
+//Corners returns the four corners of the box
+func (o OrientedBox) Corners() [][2]float64 {
+	return rectCorners(o.CX, o.CY, o.W, o.H, o.Angle)
+}
+
+//Area is the box area
+func (o OrientedBox) Area() float64 {
+	return o.W * o.H
+}
+
+//Enclosing returns the smallest axis aligned box [x1,y1,x2,y2] that contains the box
+func (o OrientedBox) Enclosing() []float64 {
+	c, s := math.Abs(math.Cos(o.Angle)), math.Abs(math.Sin(o.Angle))
+	hw := (o.W*c + o.H*s) / 2
+	hh := (o.W*s + o.H*c) / 2
+	return []float64{o.CX - hw, o.CY - hh, o.CX + hw, o.CY + hh}
+}
+
+//RotatedIOU computes the exact IOU of two oriented boxes [cx,cy,w,h,angle] by polygon clipping
+func RotatedIOU(bbox1 []float64, bbox2 []float64) float64 {
+	o1, o2 := NewOrientedBox(bbox1), NewOrientedBox(bbox2)
+	a1, a2 := o1.Area(), o2.Area()
+	//far apart boxes don't need clipping
+	if math.Hypot(o1.CX-o2.CX, o1.CY-o2.CY) >= (math.Hypot(o1.W, o1.H)+math.Hypot(o2.W, o2.H))/2 {
+		return 0
+	}
+	p := clipPolygon(o1.Corners(), o2.Corners())
+	if len(p) < 3 {
+		return 0
+	}
+	inter := polygonArea(p)
+	union := a1 + a2 - inter
+	if union <= 0 {
+		return 0
+	}
+	return inter / union
+}
+
+//ConstantVelocityRotated is the original SORT model with the box angle. It tracks oriented boxes [cx,cy,w,h,angle].
+//State is [x,y,s,r,a,vx,vy,vs,va] where s is the area, r the aspect ratio w/h and a the angle
+type ConstantVelocityRotated struct {
+	//ProcessNoise scales the process noise covariance Q
+	ProcessNoise float64
+}
+
+//Name identifies the model in configurations
+func (m ConstantVelocityRotated) Name() string {
+	return "constant-velocity-rotated"
+}
+
+//System returns the discrete linear system, its noise and the initial state covariance
+func (m ConstantVelocityRotated) System() (lti.Discrete, kalman.Noise, *mat.Dense) {
+	q := m.ProcessNoise
+	ad := identity(9)
+	c := mat.NewDense(5, 9, nil)
+	for i := 0; i < 5; i++ {
+		c.Set(i, i, 1)
+	}
+	//x, y, s and a have velocities, the aspect ratio is constant
+	for _, p := range [][2]int{{0, 5}, {1, 6}, {2, 7}, {4, 8}} {
+		ad.Set(p[0], p[1], 1)
+	}
+	sys := lti.Discrete{
+		Ad: ad,
+		Bd: mat.NewDense(9, 9, nil),
+		C:  c,
+		D:  mat.NewDense(5, 9, nil),
+	}
+	qd := []float64{q, q, q, q, 0.01 * q, 0.01 * q, 0.01 * q, 0.0001 * q, 0.0001 * q}
+	rd := []float64{1, 1, 10, 10, 0.01}
+	pd := []float64{10, 10, 10, 10, 1, 1000, 10, 10, 0.01}
+	nse := kalman.Noise{Q: diagonal(qd), R: diagonal(rd)}
+	return sys, nse, diagonal(pd)
+}
+
+//ToMeasurement converts an oriented box to the measurement vector [x,y,s,r,a]
+func (m ConstantVelocityRotated) ToMeasurement(bbox []float64) []float64 {
+	return []float64{bbox[0], bbox[1], bbox[2] * bbox[3], bbox[2] / bbox[3], bbox[4]}
+}
+
+//ToBox converts a state vector back to an oriented box [cx,cy,w,h,angle] with angle in [-pi/2,pi/2)
+func (m ConstantVelocityRotated) ToBox(x mat.Vector) []float64 {
+	w := math.Sqrt(x.AtVec(2) * x.AtVec(3))
+	return []float64{x.AtVec(0), x.AtVec(1), w, x.AtVec(2) / w, wrapHalfAngle(x.AtVec(4))}
+}
+
+//Velocity returns the box center velocity in pixels per frame
+func (m ConstantVelocityRotated) Velocity(x mat.Vector) []float64 {
+	return []float64{x.AtVec(5), x.AtVec(6)}
+}
+
+//Constrain avoids predicting negative areas and keeps the angle in [-pi/2,pi/2)
+func (m ConstantVelocityRotated) Constrain(x *mat.VecDense) {
+	if x.AtVec(7)+x.AtVec(2) <= 0 {
+		x.SetVec(7, 0.0)
+	}
+	x.SetVec(4, wrapHalfAngle(x.AtVec(4)))
+}
+
+//BoxSize is the number of values of an oriented box
+func (m ConstantVelocityRotated) BoxSize() int {
+	return 5
+}
+
+//ValidBox tells whether an oriented box is large enough to be tracked
+func (m ConstantVelocityRotated) ValidBox(bbox []float64) bool {
+	return bbox[2]*bbox[3] >= 1
+}
+
+//EnclosingBox returns the smallest [x1,y1,x2,y2] box containing an oriented box
+func (m ConstantVelocityRotated) EnclosingBox(bbox []float64) []float64 {
+	return NewOrientedBox(bbox).Enclosing()
+}
+
+//AlignMeasurement changes the measured box to the equivalent box closest to the state. A box is the same when
+//rotated by 180 degrees, or by 90 degrees with width and height swapped, and detectors report any of them
+func (m ConstantVelocityRotated) AlignMeasurement(z []float64, x mat.Vector) {
+	z[4] = alignAngle(z[4], x.AtVec(4), math.Pi)
+	d := z[4] - x.AtVec(4)
+	if math.Abs(d) > math.Pi/4 && z[3] > 0 {
+		z[3] = 1 / z[3]
+		z[4] -= math.Copysign(math.Pi/2, d)
+	}
+}
+
+//wrapHalfAngle returns the equivalent angle modulo pi in [-pi/2,pi/2)
+func wrapHalfAngle(a float64) float64 {
+	return wrapAngle(2*a) / 2
+}
+
+func diagonal(d []float64) *mat.Dense {
+	m := mat.NewDense(len(d), len(d), nil)
+	for i, v := range d {
+		m.Set(i, i, v)
+	}
+	return m
+}
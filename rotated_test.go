@@ -0,0 +1,108 @@
+package sort
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRotatedIOU(t *testing.T) {
+	a := []float64{10, 10, 4, 2, 0}
+	if math.Abs(RotatedIOU(a, a)-1) > 1e-9 {
+		t.Errorf("same boxes should have IOU 1")
+	}
+	//the same box described with swapped sides
+	if math.Abs(RotatedIOU(a, []float64{10, 10, 2, 4, math.Pi / 2})-1) > 1e-9 {
+		t.Errorf("equivalent boxes should have IOU 1")
+	}
+	if math.Abs(RotatedIOU(a, []float64{12, 10, 4, 2, 0})-1.0/3) > 1e-9 {
+		t.Errorf("unexpected iou %f", RotatedIOU(a, []float64{12, 10, 4, 2, 0}))
+	}
+	inter := 8 * (math.Sqrt2 - 1)
+	if math.Abs(RotatedIOU([]float64{0, 0, 2, 2, 0}, []float64{0, 0, 2, 2, math.Pi / 4})-inter/(8-inter)) > 1e-9 {
+		t.Errorf("unexpected rotated iou")
+	}
+	e := NewOrientedBox([]float64{10, 10, 4, 2, math.Pi / 2}).Enclosing()
+	if math.Abs(e[0]-9) > 1e-9 || math.Abs(e[1]-8) > 1e-9 || math.Abs(e[2]-11) > 1e-9 || math.Abs(e[3]-12) > 1e-9 {
+		t.Errorf("unexpected enclosing box %v", e)
+	}
+}
+
+func TestRotatedTracking(t *testing.T) {
+	s, err := NewSORT(WithMotionModel("constant-velocity-rotated"), WithCostFunction("rotated-iou"), WithFrameSize(640, 480))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//a ship turning through the angle wrap around, reported in the different equivalent forms
+	ids := map[int64]bool{}
+	var last Track
+	for f := 0; f < 40; f++ {
+		a := 1.2 + 0.03*float64(f)
+		box := []float64{200 + 3*float64(f), 200, 80, 20, a}
+		switch f % 3 {
+		case 1:
+			box[4] = a - math.Pi
+		case 2:
+			box[2], box[3], box[4] = 20, 80, a-math.Pi/2
+		}
+		err = s.Update([][]float64{append(box, 0.8)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tr := range s.Tracks() {
+			ids[tr.ID] = true
+			last = tr
+		}
+	}
+	if len(ids) != 1 {
+		t.Fatalf("ship should keep its ID. ids=%v", ids)
+	}
+	if len(last.BBox) != 5 || last.Score != 0.8 || math.Abs(last.Velocity[0]-3) > 0.2 {
+		t.Errorf("unexpected track %+v", last)
+	}
+	st := s.Trackers[0].MotionModel.ToBox(s.Trackers[0].KalmanFilter.CurrentState())
+	if RotatedIOU(st, []float64{317, 200, 80, 20, 1.2 + 0.03*39}) < 0.8 {
+		t.Errorf("state should follow the rotation. state=%v", st)
+	}
+	if st[4] < -math.Pi/2 || st[4] >= math.Pi/2 {
+		t.Errorf("angle should be wrapped. angle=%f", st[4])
+	}
+}
+
+func TestRotatedCostFunction(t *testing.T) {
+	for _, mc := range [][]string{{"constant-velocity", "rotated-iou"}, {"constant-velocity-3d", "rotated-iou"}, {"constant-velocity-rotated", "iou"}} {
+		_, err := NewSORT(WithMotionModel(mc[0]), WithCostFunction(mc[1]))
+		if err == nil {
+			t.Errorf("cost function %s should be rejected with motion model %s", mc[1], mc[0])
+		}
+	}
+}
+
+func TestRotatedSmoothing(t *testing.T) {
+	s, err := NewSORT(WithMotionModel("constant-velocity-rotated"), WithCostFunction("rotated-iou"), WithHistory(),
+		WithMinUpdatesUsePrediction(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//an almost vertical box whose angle is reported on both sides of the wrap around at +-pi/2
+	for f := 0; f < 30; f++ {
+		a := 1.56
+		if f%2 == 1 {
+			a = -1.56
+		}
+		err = s.Update([][]float64{{200 + 2*float64(f), 200, 80, 20, a, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.Trackers) != 1 {
+		t.Fatalf("expected 1 tracker, got %d", len(s.Trackers))
+	}
+	h := s.Trackers[0].History
+	for _, es := range [][]Estimate{h.Filtered(), h.Smoothed()} {
+		for _, e := range es {
+			if math.Abs(wrapHalfAngle(e.BBox[4]-math.Pi/2)) > 0.1 {
+				t.Fatalf("angle should stay near +-pi/2. frame=%d bbox=%v", e.Frame, e.BBox)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package sort
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/flaviostutz/kalman"
+	"gonum.org/v1/gonum/mat"
+)
+
+//Snapshot is a serializable copy of the state of a SORT session
+type Snapshot struct {
+	Config     Config            `json:"config"`
+	FrameCount int               `json:"frameCount"`
+	Trackers   []TrackerSnapshot `json:"trackers"`
+	//Updated has the IDs of the trackers matched during the last Update
+	Updated []int64 `json:"updated"`
+}
+
+//TrackerSnapshot is a serializable copy of the state of a KalmanBoxTracker
+type TrackerSnapshot struct {
+	ID                    int64      `json:"id"`
+	Updates               int        `json:"updates"`
+	Predicts              int        `json:"predicts"`
+	PredictsSinceUpdate   int        `json:"predictsSinceUpdate"`
+	UpdatesWithoutPredict int        `json:"updatesWithoutPredict"`
+	SkipPredicts          int        `json:"skipPredicts"`
+	FramesSinceUpdate     int        `json:"framesSinceUpdate"`
+	LastBBox              []float64  `json:"lastBBox"`
+	LastBBoxIOU           []float64  `json:"lastBBoxIOU,omitempty"`
+	LastResiduals         []float64  `json:"lastResiduals"`
+	Class                 string     `json:"class,omitempty"`
+	Embedding             []float64  `json:"embedding,omitempty"`
+	Velocity              []float64  `json:"velocity,omitempty"`
+	Confidence            float64    `json:"confidence"`
+	Exiting               bool       `json:"exiting,omitempty"`
+	Keypoints             []Keypoint `json:"keypoints,omitempty"`
+	Mask                  *RLE       `json:"mask,omitempty"`
+	//X is the Kalman state, P its covariance (row major) and State the last filtered state
+	X     []float64 `json:"x"`
+	P     []float64 `json:"p"`
+	State []float64 `json:"state"`
+}
+
+//Snapshot copies the current session state
+func (s *SORT) Snapshot() Snapshot {
+	snap := Snapshot{
+		Config:     s.config,
+		FrameCount: s.FrameCount,
+		Trackers:   make([]TrackerSnapshot, 0, len(s.Trackers)),
+		Updated:    make([]int64, 0, len(s.updated)),
+	}
+	for _, trk := range s.Trackers {
+		snap.Trackers = append(snap.Trackers, TrackerSnapshot{
+			ID:                    trk.ID,
+			Updates:               trk.Updates,
+			Predicts:              trk.Predicts,
+			PredictsSinceUpdate:   trk.PredictsSinceUpdate,
+			UpdatesWithoutPredict: trk.UpdatesWithoutPredict,
+			SkipPredicts:          trk.SkipPredicts,
+			FramesSinceUpdate:     trk.FramesSinceUpdate,
+			LastBBox:              copyOf(trk.LastBBox),
+			LastBBoxIOU:           copyOf(trk.LastBBoxIOU),
+			LastResiduals:         copyOf(trk.LastResiduals),
+			Class:                 trk.Class,
+			Embedding:             copyOf(trk.Embedding),
+			Velocity:              copyOf(trk.Velocity),
+			Confidence:            trk.Confidence,
+			Exiting:               trk.Exiting,
+			Keypoints:             copyKeypoints(trk.Keypoints),
+			Mask:                  copyMask(trk.Mask),
+			X:                     vecData(trk.KalmanCtx.X),
+			P:                     mat.DenseCopyOf(trk.KalmanCtx.P).RawMatrix().Data,
+			State:                 vecData(trk.KalmanFilter.CurrentState()),
+		})
+	}
+	for id := range s.updated {
+		snap.Updated = append(snap.Updated, id)
+	}
+	return snap
+}
+
+//Restore creates a SORT session from a snapshot. opts are applied after the snapshot config.
+//Snapshots don't keep tracker histories, the One Euro filter state of keypoints or options that are not part of
+//Config, like deletion policies and score noise functions. Restored trackers start without history, their keypoint
+//smoothing starts over and those options must be given again in opts
+func Restore(snap Snapshot, opts ...Option) (*SORT, error) {
+	s, err := NewSORT(append([]Option{WithConfig(snap.Config)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	s.FrameCount = snap.FrameCount
+	for _, ts := range snap.Trackers {
+		trk, err := NewKalmanBoxTrackerWithModel(ts.LastBBox, s.motionModel)
+		if err != nil {
+			return nil, err
+		}
+		n := trk.KalmanCtx.X.Len()
+		if len(ts.X) != n || len(ts.P) != n*n || len(ts.State) != n {
+			return nil, fmt.Errorf("tracker %d state doesn't match motion model %s", ts.ID, s.motionModel.Name())
+		}
+		trk.ID = ts.ID
+		trk.Updates = ts.Updates
+		trk.Predicts = ts.Predicts
+		trk.PredictsSinceUpdate = ts.PredictsSinceUpdate
+		trk.UpdatesWithoutPredict = ts.UpdatesWithoutPredict
+		trk.SkipPredicts = ts.SkipPredicts
+		trk.FramesSinceUpdate = ts.FramesSinceUpdate
+		trk.LastBBoxIOU = ts.LastBBoxIOU
+		trk.LastResiduals = ts.LastResiduals
+		trk.Class = ts.Class
+		trk.Embedding = ts.Embedding
+		trk.Velocity = ts.Velocity
+		trk.Confidence = ts.Confidence
+		trk.Exiting = ts.Exiting
+		trk.Keypoints = copyKeypoints(ts.Keypoints)
+		trk.keypointFilters = make([][2]oneEuroState, len(ts.Keypoints))
+		trk.Mask = copyMask(ts.Mask)
+		trk.KalmanCtx.X = mat.NewVecDense(n, copyOf(ts.X))
+		trk.KalmanCtx.P = mat.NewDense(n, n, copyOf(ts.P))
+		trk.KalmanFilter = &restoredFilter{Filter: trk.KalmanFilter, state: mat.NewVecDense(n, copyOf(ts.State))}
+		s.Trackers = append(s.Trackers, &trk)
+		reserveID(ts.ID)
+	}
+	for _, id := range snap.Updated {
+		s.updated[id] = true
+	}
+	return s, nil
+}
+
+//reserveID makes sure new trackers won't reuse restored IDs
+func reserveID(id int64) {
+	for {
+		last := atomic.LoadInt64(&lastID)
+		if last >= id || atomic.CompareAndSwapInt64(&lastID, last, id) {
+			return
+		}
+	}
+}
+
+//restoredFilter reports the restored filtered state until the filter is applied again
+type restoredFilter struct {
+	kalman.Filter
+	state   *mat.VecDense
+	applied bool
+}
+
+func (f *restoredFilter) Apply(ctx *kalman.Context, z, ctrl *mat.VecDense) mat.Vector {
+	f.applied = true
+	return f.Filter.Apply(ctx, z, ctrl)
+}
+
+func (f *restoredFilter) CurrentState() mat.Vector {
+	if f.applied {
+		return f.Filter.CurrentState()
+	}
+	return mat.VecDenseCopyOf(f.state)
+}
+
+func vecData(v mat.Vector) []float64 {
+	d := make([]float64, v.Len())
+	for i := range d {
+		d[i] = v.AtVec(i)
+	}
+	return d
+}
+
+func copyKeypoints(v []Keypoint) []Keypoint {
+	if v == nil {
+		return nil
+	}
+	return append([]Keypoint(nil), v...)
+}
+
+func copyMask(m *RLE) *RLE {
+	if m == nil {
+		return nil
+	}
+	c := RLE{Size: m.Size, Counts: append([]int(nil), m.Counts...)}
+	return &c
+}
+
+func copyOf(v []float64) []float64 {
+	if v == nil {
+		return nil
+	}
+	return append([]float64(nil), v...)
+}
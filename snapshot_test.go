@@ -0,0 +1,96 @@
+package sort
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	s, err := NewSORT(WithMinUpdatesUsePrediction(1), WithMaxPredictsWithoutUpdate(3))
+	if err != nil {
+		t.Fatalf("Error creating SORT. err=%s", err)
+	}
+	for i := 0.0; i < 5; i++ {
+		err = s.Update([][]float64{{10 + i, 10, 30 + i, 50, 0.9}, {100, 100 + i, 130, 150 + i, 0.8}})
+		if err != nil {
+			t.Fatalf("Error updating SORT. err=%s", err)
+		}
+	}
+
+	data, err := json.Marshal(s.Snapshot())
+	if err != nil {
+		t.Fatalf("Error encoding snapshot. err=%s", err)
+	}
+	snap := Snapshot{}
+	err = json.Unmarshal(data, &snap)
+	if err != nil {
+		t.Fatalf("Error decoding snapshot. err=%s", err)
+	}
+	r, err := Restore(snap)
+	if err != nil {
+		t.Fatalf("Error restoring snapshot. err=%s", err)
+	}
+	if len(r.Tracks()) != 2 || r.FrameCount != 5 {
+		t.Errorf("Restored session differs. tracks=%v frames=%d", r.Tracks(), r.FrameCount)
+	}
+
+	dets := [][]float64{{15, 10, 35, 50, 0.9}, {100, 105, 130, 155, 0.8}}
+	err = s.Update(dets)
+	if err != nil {
+		t.Fatalf("Error updating SORT. err=%s", err)
+	}
+	err = r.Update(dets)
+	if err != nil {
+		t.Fatalf("Error updating restored SORT. err=%s", err)
+	}
+	st := s.Tracks()
+	rt := r.Tracks()
+	if len(st) != len(rt) || st[0].ID != rt[0].ID || st[1].ID != rt[1].ID {
+		t.Errorf("Restored session should track as the original. original=%v restored=%v", st, rt)
+	}
+	if !equalBBox(s.Trackers[0].CurrentPrediction(), r.Trackers[0].CurrentPrediction()) {
+		t.Errorf("Restored Kalman state differs")
+	}
+
+	//new trackers must not reuse restored IDs
+	err = r.Update([][]float64{{500, 500, 520, 540, 0.9}})
+	if err != nil {
+		t.Fatalf("Error updating restored SORT. err=%s", err)
+	}
+	for _, trk := range r.Trackers[:len(r.Trackers)-1] {
+		if trk.ID == r.Trackers[len(r.Trackers)-1].ID {
+			t.Errorf("Tracker ID reused %d", trk.ID)
+		}
+	}
+}
+
+func equalBBox(a []float64, b []float64) bool {
+	for i := range a {
+		if a[i]-b[i] > 1e-9 || b[i]-a[i] > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	s, err := NewSORT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mask := rect(400, 600, 100, 100, 200, 300)
+	err = s.UpdateDetections([]Detection{{BBox: []float64{100, 100, 200, 300}, Score: 0.9, Keypoints: pose(100, 100, true), Mask: &mask}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := s.Snapshot()
+	y := snap.Trackers[0].Keypoints[7].Y
+	mask.Counts[0] = -1
+	err = s.UpdateDetections([]Detection{{BBox: []float64{102, 100, 202, 300}, Score: 0.9, Keypoints: pose(102, 100, false)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Trackers[0].Keypoints[7].Y != y || snap.Trackers[0].Mask.Counts[0] == -1 {
+		t.Errorf("later updates should not change snapshots")
+	}
+}
@@ -10,22 +10,70 @@ import (
 
 //SORT Detection tracking
 type SORT struct {
-	maxPredictsWithoutUpdate int
-	minUpdatesUsePrediction  int
-	iouThreshold             float64
-	Trackers                 []*KalmanBoxTracker
-	FrameCount               int
+	config       Config
+	motionModel  MotionModel
+	costFunction CostFunction
+	deletion     DeletionPolicy
+	scoreNoise   ScoreNoise
+	Trackers     []*KalmanBoxTracker
+	FrameCount   int
+	//Finished has the trackers removed from the session. It is only kept WithHistory
+	Finished []*KalmanBoxTracker
+	//trackers matched or created during the last Update
+	updated map[int64]bool
+	history bool
 }
 
-//NewSORT initializes a new SORT tracking session
-func NewSORT(maxPredictsWithoutUpdate int, minUpdatesUsePrediction int, iouThreshold float64) SORT {
-	return SORT{
-		maxPredictsWithoutUpdate: maxPredictsWithoutUpdate,
-		minUpdatesUsePrediction:  minUpdatesUsePrediction,
-		iouThreshold:             iouThreshold,
-		Trackers:                 make([]*KalmanBoxTracker, 0),
-		FrameCount:               0,
+//NewSORT initializes a new SORT tracking session. Parameters not set by opts are taken from DefaultConfig
+func NewSORT(opts ...Option) (*SORT, error) {
+	s := &SORT{
+		config:     DefaultConfig(),
+		deletion:   DefaultDeletionPolicy{},
+		Trackers:   make([]*KalmanBoxTracker, 0),
+		FrameCount: 0,
+		updated:    make(map[int64]bool),
 	}
+	for _, opt := range opts {
+		err := opt(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := s.config.Validate()
+	if err != nil {
+		return nil, err
+	}
+	s.motionModel, err = newMotionModel(s.config)
+	if err != nil {
+		return nil, err
+	}
+	s.costFunction, err = NewCostFunction(s.config.CostFunction)
+	if err != nil {
+		return nil, err
+	}
+	if s.scoreNoise == nil && s.config.ScoreNoise != "" {
+		s.scoreNoise, err = NewScoreNoise(s.config.ScoreNoise)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+//Config returns the parameters used by this session
+func (s *SORT) Config() Config {
+	return s.config
+}
+
+//BoxSize returns the number of values of the boxes tracked by this session, 4 for [x1,y1,x2,y2] image boxes
+func (s *SORT) BoxSize() int {
+	return boxSize(s.motionModel)
+}
+
+//ImageBox returns the [x1,y1,x2,y2] image box enclosing a box tracked by this session, or nil when its motion model
+//has no image boxes, as 3D boxes
+func (s *SORT) ImageBox(bbox []float64) []float64 {
+	return imageBox(s.motionModel, bbox)
 }
 
 //Update update trackers from detections
@@ -35,8 +83,39 @@ func NewSORT(maxPredictsWithoutUpdate int, minUpdatesUsePrediction int, iouThres
 //     Returns the a similar array, where the last column is the object ID.
 //     NOTE: The number of objects returned may differ from the number of detections provided.
 func (s *SORT) Update(dets [][]float64) error {
-	logrus.Debugf("SORT Update dets=%v iouThreshold=%f", dets, s.iouThreshold)
+	return s.update(dets, nil)
+}
+
+//UpdateDetections update trackers from detections that may carry class and appearance embeddings.
+//     Requires: this method must be called once for each frame even with empty detections.
+func (s *SORT) UpdateDetections(dets []Detection) error {
+	n := boxSize(s.motionModel)
+	bboxes := make([][]float64, len(dets))
+	_, points := s.motionModel.(ConstantVelocityPoint)
+	for i, d := range dets {
+		//points may omit the radius
+		if points && len(d.BBox) == 2 {
+			d.BBox = []float64{d.BBox[0], d.BBox[1], 0}
+		}
+		if len(d.BBox) < n {
+			return fmt.Errorf("bbox should contain at least %d positions", n)
+		}
+		bboxes[i] = append(copyOf(d.BBox[:n]), d.Score)
+	}
+	return s.update(bboxes, dets)
+}
+
+//update runs a tracking step. attrs is either nil or has the same order as dets
+func (s *SORT) update(dets [][]float64, attrs []Detection) error {
+	logrus.Debugf("SORT Update dets=%v iouThreshold=%f", dets, s.config.IOUThreshold)
+	n := boxSize(s.motionModel)
+	for _, d := range dets {
+		if len(d) < n {
+			return fmt.Errorf("bbox should contain at least %d positions", n)
+		}
+	}
 	s.FrameCount = s.FrameCount + 1
+	s.updated = make(map[int64]bool)
 
 	//NOT SURE HOW KALMAN ALGO WILL SHOW ERRORS. SEE LATER AND REMOVE INVALID PREDICTORS
 	// trks := make([]KalmanBoxTracker, 0)
@@ -56,7 +135,7 @@ func (s *SORT) Update(dets [][]float64) error {
 	//     for t in reversed(to_del):
 	//       self.trackers.pop(t)
 
-	matched, unmatchedDets, unmatchedTrks := associateDetectionsToTrackers(dets, s.Trackers, s.iouThreshold, s.minUpdatesUsePrediction)
+	matched, unmatchedDets, unmatchedTrks := associateDetectionsToTrackers(dets, attrs, s.Trackers, s.costFunction, s.config)
 
 	logrus.Debugf("Detection X Trackers. matched=%v unmatchedDets=%v unmatchedTrks=%v", matched, unmatchedDets, unmatchedTrks)
 
@@ -68,10 +147,16 @@ func (s *SORT) Update(dets [][]float64) error {
 			for _, det := range matched {
 				if det[1] == t {
 					bbox := dets[det[0]]
+					match := s.matchScore(bbox, tracker)
+					tracker.scaleNoise(noiseScale(s.scoreNoise, bbox, n))
 					_, err := tracker.Update(bbox)
 					if err != nil {
 						return err
 					}
+					tracker.raiseConfidence(detectionScore(bbox, n), match)
+					setAttributes(tracker, attrs, det[0], s.config.KeypointSmoothing)
+					tracker.updateVelocity(s.config.VelocitySmoothing)
+					s.updated[tracker.ID] = true
 					logrus.Debugf("Tracker updated. id=%d bbox=%v updates=%d\n", tracker.ID, bbox, tracker.Updates)
 					break
 				}
@@ -84,32 +169,65 @@ func (s *SORT) Update(dets [][]float64) error {
 	// create and initialise new trackers for unmatched detections
 	for _, udet := range unmatchedDets {
 
-		aread := Area(dets[udet])
-		if aread < 1 {
-			logrus.Debugf("Ignoring too small detection. bbox=%f area=%f", dets[udet], aread)
+		if !s.validBox(dets[udet]) {
+			logrus.Debugf("Ignoring too small detection. bbox=%f", dets[udet])
 			continue
 		}
 
-		trk, err := NewKalmanBoxTracker(dets[udet])
+		trk, err := NewKalmanBoxTrackerWithModel(dets[udet], s.motionModel)
 		if err != nil {
 			return err
 		}
+		setAttributes(&trk, attrs, udet, s.config.KeypointSmoothing)
+		trk.initConfidence(detectionScore(dets[udet], n))
+		trk.updateVelocity(s.config.VelocitySmoothing)
 		s.Trackers = append(s.Trackers, &trk)
+		s.updated[trk.ID] = true
 		logrus.Debugf("New tracker added. id=%d bbox=%v\n", trk.ID, trk.LastBBox)
 	}
 
+	for _, trk := range s.Trackers {
+		if !s.updated[trk.ID] {
+			trk.FramesSinceUpdate++
+			trk.decayConfidence(s.config.ConfidenceDecay)
+		}
+	}
+
+	if s.history {
+		for _, trk := range s.Trackers {
+			if trk.History == nil {
+				trk.History = NewHistory(trk, s.FrameCount)
+			} else if s.updated[trk.ID] {
+				trk.History.Add(s.FrameCount, trk, trk.LastBBox)
+			} else {
+				trk.History.Add(s.FrameCount, trk, nil)
+			}
+		}
+	}
+
 	//remove dead trackers
 	ti := len(s.Trackers)
 	for t := ti - 1; t >= 0; t-- {
 		trk := s.Trackers[t]
 		//         if((trk.time_since_update < 1) and (trk.hit_streak >= self.min_hits or self.frame_count <= self.min_hits)):
 		//           ret.append(np.concatenate((d,[trk.id+1])).reshape(1,-1)) # +1 as MOT benchmark requires positive
-		if trk.PredictsSinceUpdate > s.maxPredictsWithoutUpdate || trk.SkipPredicts > s.minUpdatesUsePrediction+1 {
+		state := SessionState{Config: s.config, FrameCount: s.FrameCount, Updated: s.updated[trk.ID], Trackers: ti}
+		if s.deletion.Delete(trk, state) {
 			s.Trackers = append(s.Trackers[:t], s.Trackers[t+1:]...)
+			if s.history {
+				s.Finished = append(s.Finished, trk)
+			}
 			logrus.Debugf("Tracker removed. id=%d, bbox=%v updates=%d\n", trk.ID, trk.LastBBox, trk.Updates)
 		}
 	}
 
+	if s.config.HasFrame() {
+		for _, trk := range s.Trackers {
+			b := predictedBox(trk)
+			trk.Exiting = b != nil && CrossesFrame(b, s.config.FrameWidth, s.config.FrameHeight)
+		}
+	}
+
 	ct := ""
 	for _, v := range s.Trackers {
 		ct = ct + fmt.Sprintf("[id=%d bbox=%v updates=%d] ", v.ID, v.LastBBox, v.Updates)
@@ -119,6 +237,24 @@ func (s *SORT) Update(dets [][]float64) error {
 	return nil
 }
 
+//matchScore scores the detection matched to a tracker between 0 and 1
+func (s *SORT) matchScore(bbox []float64, trk *KalmanBoxTracker) float64 {
+	if !distanceCost(s.config.CostFunction) {
+		return s.costFunction(bbox, trk.LastBBoxIOU)
+	}
+	var cov []float64
+	return 1 - pointDistance(s.config, trk, bbox, trk.LastBBoxIOU, &cov)/s.config.MaxPointDistance
+}
+
+//validBox tells whether a detection can start a tracker
+func (s *SORT) validBox(bbox []float64) bool {
+	bm, ok := s.motionModel.(interface{ ValidBox([]float64) bool })
+	if ok {
+		return bm.ValidBox(bbox)
+	}
+	return Area(bbox) >= 1
+}
+
 func contains(list []int, value int) bool {
 	found := false
 	for _, v := range list {
@@ -132,7 +268,12 @@ func contains(list []int, value int) bool {
 
 //   Assigns detections to tracked object (both represented as bounding boxes)
 //   Returns 3 lists of indexes: matches, unmatched_detections and unmatched_trackers
-func associateDetectionsToTrackers(detections [][]float64, trackers []*KalmanBoxTracker, iouThreshold float64, minUpdatesUsePrediction int) ([][]int, []int, []int) {
+func associateDetectionsToTrackers(detections [][]float64, attrs []Detection, trackers []*KalmanBoxTracker, costFunction CostFunction, c Config) ([][]int, []int, []int) {
+	oks := c.CostFunction == "oks"
+	maskIOU := c.CostFunction == "mask-iou"
+	distance := distanceCost(c.CostFunction)
+	iouThreshold := c.IOUThreshold
+	minUpdatesUsePrediction := c.MinUpdatesUsePrediction
 	if len(trackers) == 0 {
 		det := make([]int, 0)
 		for i := range detections {
@@ -162,11 +303,15 @@ func associateDetectionsToTrackers(detections [][]float64, trackers []*KalmanBox
 	// mm := munkres.NewMatrix(ld, lt)
 	//initialize IOUS cost matrix
 	ious := make([][]float64, ld)
+	gated := make([][]bool, ld)
 	for i := 0; i < len(ious); i++ {
 		ious[i] = make([]float64, lt)
+		gated[i] = make([]bool, lt)
 	}
 
 	predicted := make([]bool, lt)
+	masks := make([]*RLE, lt)
+	covs := make([][]float64, lt)
 	for d := 0; d < ld; d++ {
 		// iouMatrix[d] = make([]float64, lt)
 		for t := 0; t < lt; t++ {
@@ -184,6 +329,8 @@ func associateDetectionsToTrackers(detections [][]float64, trackers []*KalmanBox
 				} else {
 					tbbox = trk.CurrentPrediction()
 				}
+				//detectors don't report the parts of objects outside the image
+				tbbox = c.clip(trk.MotionModel, tbbox)
 			} else {
 				trk.SkipPredicts = trk.SkipPredicts + 1
 			}
@@ -191,13 +338,45 @@ func associateDetectionsToTrackers(detections [][]float64, trackers []*KalmanBox
 			// tbbox1 := trk.LastBBox
 			// tbbox = ResizeFromCenter(trk.LastBBox, 4.0)
 			// fmt.Printf("ioubbox - %v %v", tbbox, tbbox1)
-			v := IOU(detections[d], tbbox) //+ AreaMatch(detections[d], tbbox1) + RatioMatch(detections[d], tbbox1)
+			var v float64
+			if distance {
+				//distances are gated by MaxPointDistance instead of scored
+				dist := pointDistance(c, trk, detections[d], tbbox, &covs[t])
+				v = 1 - dist/c.MaxPointDistance
+				gated[d][t] = dist > c.MaxPointDistance
+			} else {
+				v = costFunction(detections[d], tbbox) //+ AreaMatch(detections[d], tbbox1) + RatioMatch(detections[d], tbbox1)
+			}
+			//oks falls back to box IOU for detections or trackers without keypoints
+			if oks && attrs != nil && len(attrs[d].Keypoints) > 0 && len(attrs[d].Keypoints) == len(trk.Keypoints) {
+				v = OKS(attrs[d].Keypoints, trk.predictedKeypoints(tbbox), Area(detections[d]), c.KeypointSigmas)
+			}
+			//mask-iou falls back to box IOU for detections or trackers without masks
+			if maskIOU && attrs != nil && attrs[d].Mask != nil && trk.Mask != nil && attrs[d].Mask.Size == trk.Mask.Size {
+				if masks[t] == nil {
+					m := trk.predictedMask(tbbox)
+					masks[t] = &m
+				}
+				v = MaskIOU(*attrs[d].Mask, *masks[t])
+			}
 			trk.LastBBoxIOU = tbbox
 			// if v > 0 {
 			logrus.Debugf("IOU=%v detbbox=%v trackerrefbbox=%v trackerid=%d lastbbox=%v", v, detections[d], tbbox, trackers[t].ID, trackers[t].LastBBox)
 			// }
 			//invert cost matrix (we want max cost here)
 			ious[d][t] = 1 - v
+			//pairs too far apart on the ground plane are never matched
+			if c.MaxDistance > 0 && c.gated(trk.MotionModel, detections[d], tbbox) {
+				gated[d][t] = true
+			}
+			//detections above the horizon would put NaNs in the state of ground plane trackers
+			gp, ok := trk.MotionModel.(GroundPlane)
+			if ok && !gp.measurable(detections[d]) {
+				gated[d][t] = true
+			}
+			if gated[d][t] {
+				ious[d][t] = 3
+			}
 		}
 	}
 
@@ -246,7 +425,7 @@ func associateDetectionsToTrackers(detections [][]float64, trackers []*KalmanBox
 	for _, mi := range matchedIndices {
 		//filter out matched with low IOU
 		iou := 1 - ious[mi[0]][mi[1]]
-		if iou < iouThreshold {
+		if (!distance && iou < iouThreshold) || gated[mi[0]][mi[1]] {
 			logrus.Debugf("Skipping detection/tracker because it has low IOU deti=%d trki=%d iou=%f", mi[0], mi[1], iou)
 			unmatchedDetections = append(unmatchedDetections, mi[0])
 			unmatchedTrackers = append(unmatchedTrackers, mi[1])
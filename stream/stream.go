@@ -0,0 +1,164 @@
+//Package stream tracks objects from frames received as JSON lines, keeping one SORT session for each stream ID.
+//It is meant to be fed by a detector process through a pipe
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/flaviostutz/sort"
+	"github.com/sirupsen/logrus"
+)
+
+//Frame is one input line
+type Frame struct {
+	Frame int `json:"frame"`
+	//Timestamp is copied to the output as is
+	Timestamp float64 `json:"timestamp,omitempty"`
+	//Stream identifies the camera or video. Frames from different streams may be interleaved
+	Stream     string           `json:"stream,omitempty"`
+	Detections []sort.Detection `json:"detections"`
+}
+
+//TrackedFrame is one output line. Exactly one is written for each input line
+type TrackedFrame struct {
+	Frame     int          `json:"frame"`
+	Timestamp float64      `json:"timestamp,omitempty"`
+	Stream    string       `json:"stream,omitempty"`
+	Tracks    []sort.Track `json:"tracks"`
+	//Error is set when the input line couldn't be processed
+	Error string `json:"error,omitempty"`
+}
+
+//Processor runs SORT over frames of multiple streams
+type Processor struct {
+	queueSize int
+	opts      []sort.Option
+	sessions  map[string]*session
+}
+
+type session struct {
+	sort      *sort.SORT
+	lastFrame int
+}
+
+//NewProcessor creates a processor whose sessions are created with opts. queueSize is the number
+//of input lines read ahead of processing. When the queue is full the input is not read anymore,
+//so that a fast producer is blocked by the pipe instead of making memory and latency grow
+func NewProcessor(queueSize int, opts ...sort.Option) (*Processor, error) {
+	if queueSize < 1 {
+		return nil, fmt.Errorf("queueSize must be >= 1")
+	}
+	_, err := sort.NewSORT(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Processor{
+		queueSize: queueSize,
+		opts:      opts,
+		sessions:  make(map[string]*session),
+	}, nil
+}
+
+//Run reads frames from r until EOF or ctx is done and writes tracked frames to w.
+//Output is flushed whenever no other input line is waiting, which bounds latency while batching writes during bursts
+func (p *Processor) Run(ctx context.Context, r io.Reader, w io.Writer) error {
+	lines := make(chan []byte, p.queueSize)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		errc <- scanner.Err()
+	}()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for {
+		select {
+		case <-ctx.Done():
+			bw.Flush()
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				err := bw.Flush()
+				if err != nil {
+					return err
+				}
+				return <-errc
+			}
+			if len(line) == 0 {
+				continue
+			}
+			err := enc.Encode(p.Process(line))
+			if err != nil {
+				return err
+			}
+			if len(lines) == 0 {
+				err = bw.Flush()
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+//Process tracks a single JSON encoded Frame
+func (p *Processor) Process(line []byte) TrackedFrame {
+	f := Frame{}
+	err := json.Unmarshal(line, &f)
+	if err != nil {
+		return TrackedFrame{Error: fmt.Sprintf("invalid frame. err=%s", err)}
+	}
+	return p.ProcessFrame(f)
+}
+
+//ProcessFrame tracks a decoded Frame in the session of its stream, creating the session if needed
+func (p *Processor) ProcessFrame(f Frame) TrackedFrame {
+	out := TrackedFrame{Frame: f.Frame, Timestamp: f.Timestamp, Stream: f.Stream, Tracks: []sort.Track{}}
+	ss, ok := p.sessions[f.Stream]
+	if !ok {
+		s, err := sort.NewSORT(p.opts...)
+		if err != nil {
+			out.Error = err.Error()
+			return out
+		}
+		ss = &session{sort: s}
+		p.sessions[f.Stream] = ss
+		logrus.Debugf("New stream session. stream=%s", f.Stream)
+	}
+	if f.Frame != 0 && f.Frame <= ss.lastFrame {
+		out.Error = fmt.Sprintf("frame %d is not after frame %d", f.Frame, ss.lastFrame)
+		return out
+	}
+	err := ss.sort.UpdateDetections(f.Detections)
+	if err != nil {
+		out.Error = err.Error()
+		return out
+	}
+	ss.lastFrame = f.Frame
+	out.Tracks = ss.sort.Tracks()
+	return out
+}
+
+//Streams returns the IDs of the streams seen so far
+func (p *Processor) Streams() []string {
+	ids := make([]string, 0, len(p.sessions))
+	for id := range p.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
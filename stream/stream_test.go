@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/flaviostutz/sort"
+)
+
+func TestRunInterleavedStreams(t *testing.T) {
+	p, err := NewProcessor(2, sort.WithMinUpdatesUsePrediction(1))
+	if err != nil {
+		t.Fatalf("Error creating processor. err=%s", err)
+	}
+
+	inr, inw := io.Pipe()
+	outr, outw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Run(context.Background(), inr, outw)
+		outw.Close()
+	}()
+	out := bufio.NewScanner(outr)
+
+	//each output must be available before the next input is written
+	send := func(line string) TrackedFrame {
+		_, err := inw.Write([]byte(line + "\n"))
+		if err != nil {
+			t.Fatalf("Error writing input. err=%s", err)
+		}
+		if !out.Scan() {
+			t.Fatalf("No output for %s", line)
+		}
+		tf := TrackedFrame{}
+		err = json.Unmarshal(out.Bytes(), &tf)
+		if err != nil {
+			t.Fatalf("Invalid output %s", out.Text())
+		}
+		return tf
+	}
+
+	a1 := send(`{"frame":1,"timestamp":10.5,"stream":"a","detections":[{"bbox":[10,10,30,50],"class":"person"}]}`)
+	b1 := send(`{"frame":1,"stream":"b","detections":[{"bbox":[200,10,230,50],"score":0.8}]}`)
+	a2 := send(`{"frame":2,"stream":"a","detections":[{"bbox":[11,10,31,50],"class":"person"}]}`)
+	if a1.Timestamp != 10.5 || len(a1.Tracks) != 1 || a1.Tracks[0].Class != "person" {
+		t.Errorf("Unexpected output %+v", a1)
+	}
+	if b1.Stream != "b" || len(b1.Tracks) != 1 || b1.Tracks[0].ID == a1.Tracks[0].ID {
+		t.Errorf("Streams should have separate trackers. a=%+v b=%+v", a1, b1)
+	}
+	if len(a2.Tracks) != 1 || a2.Tracks[0].ID != a1.Tracks[0].ID {
+		t.Errorf("Stream a should keep its track. a1=%+v a2=%+v", a1, a2)
+	}
+
+	old := send(`{"frame":2,"stream":"a","detections":[]}`)
+	if old.Error == "" {
+		t.Errorf("Repeated frame should be reported")
+	}
+	bad := send(`{"frame":`)
+	if bad.Error == "" {
+		t.Errorf("Invalid JSON should be reported")
+	}
+
+	inw.Close()
+	err = <-done
+	if err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	if len(p.Streams()) != 2 {
+		t.Errorf("Expected 2 streams, found %v", p.Streams())
+	}
+}
+
+func TestProcess3DBoxes(t *testing.T) {
+	p, err := NewProcessor(1, sort.WithMotionModel("constant-velocity-3d"), sort.WithCostFunction("iou-3d"), sort.WithMinUpdatesUsePrediction(1))
+	if err != nil {
+		t.Fatalf("Error creating processor. err=%s", err)
+	}
+	f1 := p.Process([]byte(`{"frame":1,"detections":[{"bbox":[0,0,0,4,2,2,0.1],"score":0.9}]}`))
+	f2 := p.Process([]byte(`{"frame":2,"detections":[{"bbox":[0.5,0,0,4,2,2,0.1],"score":0.9}]}`))
+	if f2.Error != "" || len(f2.Tracks) != 1 || len(f2.Tracks[0].BBox) != 7 || f2.Tracks[0].ID != f1.Tracks[0].ID {
+		t.Errorf("3d boxes should be tracked. f1=%+v f2=%+v", f1, f2)
+	}
+	bad := p.Process([]byte(`{"frame":3,"detections":[{"bbox":[10,10,30,50]}]}`))
+	if bad.Error == "" {
+		t.Errorf("4 value boxes should be rejected by a 3d session")
+	}
+}
@@ -0,0 +1,112 @@
+package sort
+
+import (
+	"math"
+)
+
+//Track is the state reported for a tracked object in the current frame
+type Track struct {
+	//ID is the tracker ID. It is always >= 1
+	ID int64 `json:"id"`
+	//BBox is the last detection matched to the tracker in the form [x1,y1,x2,y2], [cx,cy,w,h,angle] with
+	//oriented boxes or [x,y,z,l,w,h,yaw] with 3D motion models
+	BBox []float64 `json:"bbox"`
+	//Score is the detection score (5th detection column) or 1 if the detection had no score
+	Score float64 `json:"score"`
+	//Class is the class of the last detection matched to the tracker
+	Class string `json:"class,omitempty"`
+	//Confidence combines the matched detection scores and the tracker uncertainty. See KalmanBoxTracker.Confidence
+	Confidence float64 `json:"confidence"`
+	//Velocity is the box center velocity in pixels per frame. It is omitted when the motion model has no velocities
+	Velocity []float64 `json:"velocity,omitempty"`
+	//Heading is the direction of Velocity in degrees, clockwise from the image x axis as y grows downwards
+	Heading float64 `json:"heading"`
+	//Speed is the norm of Velocity in pixels per frame
+	Speed float64 `json:"speed,omitempty"`
+	//World is the ground plane position of the box bottom center. Only set when the session is calibrated
+	World []float64 `json:"world,omitempty"`
+	//WorldVelocity is the ground plane velocity of the box bottom center in meters per second. Only set when the session is calibrated
+	WorldVelocity []float64 `json:"worldVelocity,omitempty"`
+	//WorldSpeed is the norm of WorldVelocity in meters per second
+	WorldSpeed float64 `json:"worldSpeed,omitempty"`
+	//Keypoints are the joints of the tracked object, smoothed when the session has KeypointSmoothing
+	Keypoints []Keypoint `json:"keypoints,omitempty"`
+	//Mask is the instance mask of the last detection matched to the tracker that had one
+	Mask *RLE `json:"mask,omitempty"`
+	//Exiting is set when the predicted box crosses the frame border. Only set when the session knows the frame size
+	Exiting bool `json:"exiting,omitempty"`
+	//Interpolated is set by offline post processing on positions filled between detections
+	Interpolated bool `json:"interpolated,omitempty"`
+}
+
+//Tracks returns the trackers matched in the last Update that had enough updates to be trusted.
+//During the first frames of a session all matched trackers are returned, as sort.py does
+func (s *SORT) Tracks() []Track {
+	min := s.config.MinUpdatesUsePrediction
+	tracks := make([]Track, 0)
+	for _, trk := range s.Trackers {
+		if !s.updated[trk.ID] {
+			continue
+		}
+		if s.config.ReportConfidence > 0 {
+			if trk.Confidence < s.config.ReportConfidence {
+				continue
+			}
+		} else if trk.Updates < min && s.FrameCount > min {
+			continue
+		}
+		t := newTrack(trk)
+		if s.config.Calibration != nil {
+			s.setWorld(&t, trk)
+		}
+		tracks = append(tracks, t)
+	}
+	return tracks
+}
+
+//setWorld fills the ground plane position and velocity of t
+func (s *SORT) setWorld(t *Track, trk *KalmanBoxTracker) {
+	c := s.config.Calibration
+	wm, ok := trk.MotionModel.(WorldModel)
+	if ok {
+		//the filter already tracks the ground plane, so perspective doesn't distort velocities
+		pos, vel := wm.World(trk.KalmanFilter.CurrentState())
+		t.World = pos
+		t.WorldVelocity = []float64{vel[0] * c.FPS, vel[1] * c.FPS}
+	} else {
+		b := imageBox(trk.MotionModel, t.BBox)
+		if b == nil {
+			return
+		}
+		t.World = c.world(b)
+		if t.Velocity != nil {
+			t.WorldVelocity = c.worldVelocity(b, t.Velocity)
+		}
+	}
+	if t.WorldVelocity != nil {
+		t.WorldSpeed = math.Hypot(t.WorldVelocity[0], t.WorldVelocity[1])
+	}
+}
+
+func newTrack(trk *KalmanBoxTracker) Track {
+	n := boxSize(trk.MotionModel)
+	t := Track{
+		ID:         trk.ID,
+		BBox:       copyOf(trk.LastBBox[:n]),
+		Score:      detectionScore(trk.LastBBox, n),
+		Class:      trk.Class,
+		Confidence: trk.Confidence,
+		Exiting:    trk.Exiting,
+		Mask:       copyMask(trk.Mask),
+	}
+	if trk.Keypoints != nil {
+		t.Keypoints = make([]Keypoint, len(trk.Keypoints))
+		copy(t.Keypoints, trk.Keypoints)
+	}
+	if trk.Velocity != nil {
+		t.Velocity = copyOf(trk.Velocity)
+		t.Speed = math.Hypot(t.Velocity[0], t.Velocity[1])
+		t.Heading = math.Atan2(t.Velocity[1], t.Velocity[0]) * 180 / math.Pi
+	}
+	return t
+}
@@ -21,6 +21,22 @@ func IOU(bbox1 []float64, bbox2 []float64) float64 {
 	return o
 }
 
+//GIOU Computes Generalized IOU between two bboxes in the form [x1,y1,x2,y2].
+//It ranges from -1 to 1 and, unlike IOU, still tells how far apart boxes that don't overlap are
+func GIOU(bbox1 []float64, bbox2 []float64) float64 {
+	w := math.Max(0., math.Min(bbox1[2], bbox2[2])-math.Max(bbox1[0], bbox2[0]))
+	h := math.Max(0., math.Min(bbox1[3], bbox2[3])-math.Max(bbox1[1], bbox2[1]))
+	wh := w * h
+	union := Area(bbox1) + Area(bbox2) - wh
+	cw := math.Max(bbox1[2], bbox2[2]) - math.Min(bbox1[0], bbox2[0])
+	ch := math.Max(bbox1[3], bbox2[3]) - math.Min(bbox1[1], bbox2[1])
+	c := cw * ch
+	if union <= 0 || c <= 0 {
+		return 0
+	}
+	return wh/union - (c-union)/c
+}
+
 //RatioMatch computes how close the bbox dimensions from the two bboxes are (0-1). 1-perfect match
 func RatioMatch(bbox1 []float64, bbox2 []float64) float64 {
 	w1 := (bbox1[2] - bbox1[0])
@@ -56,7 +72,7 @@ func Area(bbox []float64) float64 {
 	return math.Abs(a * b)
 }
 
-//ResizeFromCenter resizes a bounding box by a scale factor from its center
+//ResizeFromCenter resizes a bounding box by a scale factor from its center. Use ClipBox to limit it to the frame
 func ResizeFromCenter(bbox []float64, scale float64) []float64 {
 	w := (bbox[2] - bbox[0])
 	h := (bbox[3] - bbox[1])
@@ -66,8 +82,8 @@ func ResizeFromCenter(bbox []float64, scale float64) []float64 {
 	bbox2 := make([]float64, 4)
 	bbox2[0] = math.Max(bbox[0]-dx, 0)
 	bbox2[1] = math.Max(bbox[1]-dy+h, 0)
-	bbox2[2] = math.Min(bbox[2]+dx, 99999)
-	bbox2[3] = math.Min(bbox[3]+dy+h, 99999)
+	bbox2[2] = bbox[2] + dx
+	bbox2[3] = bbox[3] + dy + h
 	return bbox2
 }
 
@@ -0,0 +1,74 @@
+package sort
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHomography(t *testing.T) {
+	//image rectangle mapped to a 10x20m ground rectangle
+	src := [][2]float64{{100, 100}, {500, 100}, {500, 500}, {100, 500}}
+	dst := [][2]float64{{0, 20}, {10, 20}, {10, 0}, {0, 0}}
+	h, err := NewHomography(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range src {
+		x, y := h.Project(src[i][0], src[i][1])
+		if math.Abs(x-dst[i][0]) > 1e-6 || math.Abs(y-dst[i][1]) > 1e-6 {
+			t.Errorf("point %d projected to %f,%f", i, x, y)
+		}
+	}
+	inv, err := h.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := inv.Project(5, 10)
+	if math.Abs(x-300) > 1e-6 || math.Abs(y-300) > 1e-6 {
+		t.Errorf("inverse projected to %f,%f", x, y)
+	}
+
+	_, err = NewHomography(src[:3], dst[:3])
+	if err == nil {
+		t.Errorf("less than 4 points should be rejected")
+	}
+}
+
+func TestTrackVelocity(t *testing.T) {
+	//40px per meter, y pointing up on the ground
+	h := Homography{0.025, 0, 0, 0, -0.025, 20, 0, 0, 1}
+	s, err := NewSORT(WithMinUpdatesUsePrediction(1), WithCalibration(Calibration{Homography: h, FPS: 25}), WithVelocitySmoothing(0.5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//box moves 2px right and 1px down per frame
+	var tracks []Track
+	for f := 0; f < 20; f++ {
+		x := 100 + 2*float64(f)
+		y := 100 + float64(f)
+		err = s.Update([][]float64{{x, y, x + 40, y + 80, 0.9}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracks = s.Tracks()
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("expected 1 track, got %d", len(tracks))
+	}
+	tr := tracks[0]
+	if math.Abs(tr.Velocity[0]-2) > 0.05 || math.Abs(tr.Velocity[1]-1) > 0.05 {
+		t.Errorf("unexpected velocity %v", tr.Velocity)
+	}
+	if math.Abs(tr.Speed-math.Sqrt(5)) > 0.05 || math.Abs(tr.Heading-26.565) > 1 {
+		t.Errorf("unexpected speed %f or heading %f", tr.Speed, tr.Heading)
+	}
+	//2px/frame * 0.025m/px * 25fps = 1.25m/s
+	if math.Abs(tr.WorldVelocity[0]-1.25) > 0.05 || math.Abs(tr.WorldVelocity[1]+0.625) > 0.05 {
+		t.Errorf("unexpected world velocity %v", tr.WorldVelocity)
+	}
+
+	_, err = NewSORT(WithCalibration(Calibration{Homography: h}))
+	if err == nil {
+		t.Errorf("calibration without fps should be rejected")
+	}
+}